@@ -0,0 +1,13 @@
+# Import a domain registered in the account, with the default purchase_years
+# of 1 and min_days_remaining of 30
+terraform import st-namecheap_domain.domain example.com
+
+# Import a domain, with purchase_years and min_days_remaining
+terraform import st-namecheap_domain.domain example.com,1,90
+
+# Import a domain, with purchase_years, min_days_remaining and max_price, so
+# that the next plan has no changes
+terraform import st-namecheap_domain.domain example.com,1,90,15.5
+
+# Import a domain of one of the accounts configured in the provider
+terraform import st-namecheap_domain.domain brand/example.com
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 
@@ -14,24 +15,37 @@ import (
 // Provider documentation generation.
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs generate --provider-name st-namecheap
 
+// version and commit are set by goreleaser.
+var (
+	version = "dev"
+	commit  = "none"
+)
+
 func main() {
-	var debug bool
+	var debug, showVersion bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.BoolVar(&showVersion, "version", false, "print the version of the provider and exit")
 	flag.Parse()
 
+	if showVersion {
+		fmt.Printf("terraform-provider-st-namecheap %s (commit %s)\n", version, commit)
+		return
+	}
+
 	providerAddress := os.Getenv("PROVIDER_LOCAL_PATH")
 	if providerAddress == "" {
 		providerAddress = "registry.terraform.io/myklst/st-namecheap"
 	}
 
-	// To enable debug mode, set `Debug` to true
+	// In debug mode the provider server prints the TF_REATTACH_PROVIDERS
+	// value to export for Terraform to connect to this process.
 	opts := providerserver.ServeOpts{
 		Address: providerAddress,
-		Debug:   false,
+		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New, opts)
+	err := providerserver.Serve(context.Background(), provider.New(version), opts)
 
 	if err != nil {
 		log.Fatal(err.Error())
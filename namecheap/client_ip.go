@@ -0,0 +1,73 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	// CLIENT_IP_AUTO as client_ip resolves the egress IP of the provider.
+	CLIENT_IP_AUTO string = "auto"
+
+	defaultClientIpResolverURL = "https://api.ipify.org"
+	clientIpResolverTimeout    = 10 * time.Second
+
+	// errNumberClientIpNotWhitelisted is the error number NameCheap reports
+	// for requests from an IP that is not whitelisted for API access.
+	errNumberClientIpNotWhitelisted = "1011150"
+)
+
+// resolveClientIp asks the resolver for the IP the provider connects from. The
+// resolver must reply with the IPv4 address as plain text.
+func resolveClientIp(ctx context.Context, httpClient *http.Client, resolverURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, clientIpResolverTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resolverURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s replied with HTTP status %s", resolverURL, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if err != nil {
+		return "", err
+	}
+
+	ip := strings.TrimSpace(string(body))
+	if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil {
+		return "", fmt.Errorf("%s replied with %q, which is not an IPv4 address", resolverURL, ip)
+	}
+	return ip, nil
+}
+
+// explainClientIp adds the automatically detected client IP to diags when
+// NameCheap rejected it for not being whitelisted.
+func explainClientIp(diags *diag.Diagnostics, clients *namecheapClients) {
+	if clients == nil || clients.clientIpResolverURL == "" {
+		return
+	}
+	for _, d := range diags.Errors() {
+		if strings.Contains(d.Summary()+d.Detail(), "("+errNumberClientIpNotWhitelisted+")") {
+			diags.AddError("Client IP is not whitelisted",
+				fmt.Sprintf("NameCheap rejected the client IP %s, which was detected automatically with %s. "+
+					"Whitelist it in the API access settings of the NameCheap account, or set client_ip to "+
+					"an IP that is whitelisted.", clients.clientIp, clients.clientIpResolverURL))
+			return
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package namecheap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestResolveClientIp(t *testing.T) {
+	for reply, expected := range map[string]string{
+		"203.0.113.7\n": "203.0.113.7",
+		"2001:db8::1":   "not an IPv4 address",
+		"<html></html>": "not an IPv4 address",
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, reply)
+		}))
+
+		ip, err := resolveClientIp(context.Background(), http.DefaultClient, srv.URL)
+		if err != nil {
+			ip = err.Error()
+		}
+		if !strings.Contains(ip, expected) {
+			t.Errorf("expected %q for reply %q, got %q", expected, reply, ip)
+		}
+		srv.Close()
+	}
+}
+
+func TestResolveClientIpHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	if _, err := resolveClientIp(context.Background(), http.DefaultClient, srv.URL); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("expected HTTP status error, got %v", err)
+	}
+}
+
+func TestExplainClientIp(t *testing.T) {
+	clients := &namecheapClients{clientIp: "203.0.113.7", clientIpResolverURL: "http://127.0.0.1/ip"}
+	rejected := diagnosticErrorOf(errors.New("Invalid request IP: 203.0.113.7 (1011150)"), "get domain [example.com] info failed")
+
+	var diags diag.Diagnostics
+	diags.Append(rejected)
+	explainClientIp(&diags, clients)
+	if len(diags) != 2 || !strings.Contains(diags[1].Detail(), "203.0.113.7, which was detected automatically") {
+		t.Errorf("expected the detected client IP to be reported, got %v", diags)
+	}
+
+	// The IP is left alone when it was configured.
+	diags = diag.Diagnostics{rejected}
+	explainClientIp(&diags, &namecheapClients{clientIp: "203.0.113.7"})
+	if len(diags) != 1 {
+		t.Errorf("expected no additional diagnostic, got %v", diags)
+	}
+}
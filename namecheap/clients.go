@@ -0,0 +1,60 @@
+package namecheap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
+)
+
+// namecheapClients holds the API clients of the provider, the one configured
+// by the top-level credentials and one per entry of the accounts block. It is
+// passed to the resources as provider data.
+type namecheapClients struct {
+	// defaultClient is nil when only the accounts block has credentials.
+	defaultClient *sdk.Client
+	accounts      map[string]*sdk.Client
+
+	// clientIp is shared by all clients. clientIpResolverURL is only set
+	// when it was detected automatically.
+	clientIp            string
+	clientIpResolverURL string
+}
+
+// clientOf returns the client of the named account, or the default client
+// when account is null.
+func (c *namecheapClients) clientOf(account types.String) (*sdk.Client, diag.Diagnostic) {
+	if account.IsNull() || account.IsUnknown() {
+		if c.defaultClient == nil {
+			return nil, diag.NewAttributeErrorDiagnostic(path.Root("account"), "Missing account",
+				"The provider has no top-level credentials, set account to one of the configured accounts: "+
+					c.accountNames())
+		}
+		return c.defaultClient, nil
+	}
+
+	client, ok := c.accounts[account.ValueString()]
+	if !ok {
+		return nil, diag.NewAttributeErrorDiagnostic(path.Root("account"), "Unknown account",
+			fmt.Sprintf("Account [%s] is not configured in the accounts block of the provider, expected one of: %s",
+				account.ValueString(), c.accountNames()))
+	}
+	return client, nil
+}
+
+func (c *namecheapClients) accountNames() string {
+	names := make([]string, 0, len(c.accounts))
+	for name := range c.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return "(none)"
+	}
+	return strings.Join(names, ", ")
+}
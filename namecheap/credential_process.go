@@ -0,0 +1,106 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// credentialProcessTimeout bounds how long the credential process may run.
+const credentialProcessTimeout = time.Minute
+
+// credentialProcessOutput is the JSON document the credential process writes
+// to its standard output.
+type credentialProcessOutput struct {
+	UserName string `json:"user_name"`
+	ApiUser  string `json:"api_user"`
+	ApiKey   string `json:"api_key"`
+	ClientIp string `json:"client_ip"`
+}
+
+// runCredentialProcess runs the command and parses the credentials it prints.
+// The command is split into arguments like a shell would, honouring single
+// and double quotes, but is not run through a shell.
+func runCredentialProcess(ctx context.Context, command string) (*credentialsProfile, error) {
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("the command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s did not finish within %s", args[0], credentialProcessTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %v: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s failed: %v", args[0], err)
+	}
+
+	var output credentialProcessOutput
+	decoder := json.NewDecoder(&stdout)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&output); err != nil {
+		// The output holds secrets, so it is not part of the error.
+		return nil, fmt.Errorf("%s printed invalid JSON, expected an object with user_name, api_user, api_key "+
+			"and client_ip: %v", args[0], err)
+	}
+
+	return &credentialsProfile{
+		UserName: output.UserName,
+		ApiUser:  output.ApiUser,
+		ApiKey:   output.ApiKey,
+		ClientIp: output.ClientIp,
+	}, nil
+}
+
+// splitCommand splits a command line into arguments on unquoted whitespace.
+func splitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+	)
+	for _, c := range command {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
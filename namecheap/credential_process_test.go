@@ -0,0 +1,79 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCredentialProcessHelper is run as the credential process by the tests
+// below, printing its argument after -- to stdout, or failing without one.
+func TestCredentialProcessHelper(t *testing.T) {
+	if os.Getenv("NAMECHEAP_TEST_CREDENTIAL_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) < 2 || args[1] == "" {
+		fmt.Fprint(os.Stderr, "missing output")
+		os.Exit(2)
+	}
+	fmt.Print(args[1])
+	os.Exit(0)
+}
+
+func credentialProcessHelper(t *testing.T, output string) string {
+	t.Setenv("NAMECHEAP_TEST_CREDENTIAL_PROCESS", "1")
+	return fmt.Sprintf("%q -test.run=TestCredentialProcessHelper -- '%s'", os.Args[0], output)
+}
+
+func TestRunCredentialProcess(t *testing.T) {
+	command := credentialProcessHelper(t, `{"user_name":"brand","api_user":"brand","api_key":"secret","client_ip":"127.0.0.1"}`)
+	profile, err := runCredentialProcess(context.Background(), command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := credentialsProfile{UserName: "brand", ApiUser: "brand", ApiKey: "secret", ClientIp: "127.0.0.1"}
+	if *profile != expected {
+		t.Errorf("expected %+v, got %+v", expected, *profile)
+	}
+}
+
+func TestRunCredentialProcessErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		command  string
+		expected string
+	}{
+		"invalid json":  {credentialProcessHelper(t, `{"api_token":"secret"}`), "invalid JSON"},
+		"failure":       {credentialProcessHelper(t, ""), "missing output"},
+		"not found":     {"namecheap-credential-process-does-not-exist", "failed"},
+		"unterminated":  {`helper "--profile`, "unterminated"},
+		"empty command": {"  ", "empty"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := runCredentialProcess(context.Background(), tc.command)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("expected error containing %q, got %v", tc.expected, err)
+			}
+			if err != nil && strings.Contains(err.Error(), "secret") {
+				t.Errorf("expected the error not to contain the output, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	args, err := splitCommand(`vault-namecheap --path "secret/name cheap" 'it''s' x""y`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"vault-namecheap", "--path", "secret/name cheap", "its", "xy"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %q, got %q", expected, args)
+	}
+}
@@ -0,0 +1,130 @@
+package namecheap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultProfile = "default"
+
+// errProfileNotFound is returned by loadCredentialsProfile when the file has
+// no section of the profile.
+var errProfileNotFound = errors.New("profile not found")
+
+// defaultCredentialsFile is where the credentials file is looked up when
+// neither shared_credentials_file nor NAMECHEAP_SHARED_CREDENTIALS_FILE is set.
+var defaultCredentialsFile = filepath.Join("~", ".namecheap", "credentials")
+
+// credentialsProfile is a profile of the credentials file. Keys that are not
+// in the profile are left empty.
+type credentialsProfile struct {
+	UserName string
+	ApiUser  string
+	ApiKey   string
+	ClientIp string
+}
+
+// merge returns the profile with the non-empty values of other taking
+// precedence.
+func (p *credentialsProfile) merge(other *credentialsProfile) *credentialsProfile {
+	merged := *p
+	for _, v := range []struct {
+		dst *string
+		src string
+	}{
+		{&merged.UserName, other.UserName},
+		{&merged.ApiUser, other.ApiUser},
+		{&merged.ApiKey, other.ApiKey},
+		{&merged.ClientIp, other.ClientIp},
+	} {
+		if v.src != "" {
+			*v.dst = v.src
+		}
+	}
+	return &merged
+}
+
+// loadCredentialsProfile reads the named profile of an INI credentials file:
+//
+//	[default]
+//	user_name = xxx
+//	api_user  = xxx
+//	api_key   = xxx
+//	client_ip = xxx.xxx.xxx.xxx
+//
+// A leading ~ of the path is expanded to the home directory.
+func loadCredentialsProfile(path string, profile string) (*credentialsProfile, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		section string
+		found   bool
+		result  credentialsProfile
+	)
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			found = found || section == profile
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value, got: %q", path, lineNo, line)
+		}
+		if section != profile {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "user_name":
+			result.UserName = value
+		case "api_user":
+			result.ApiUser = value
+		case "api_key":
+			result.ApiKey = value
+		case "client_ip":
+			result.ClientIp = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown key %q in profile [%s]", path, lineNo, strings.TrimSpace(key), profile)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: [%s] is not in %s", errProfileNotFound, profile, path)
+	}
+	return &result, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
@@ -0,0 +1,66 @@
+package namecheap
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	err := os.WriteFile(path, []byte(`
+# NameCheap accounts
+[default]
+user_name = brand
+api_user  = brand
+api_key   = brandkey
+client_ip = 127.0.0.1
+
+[legacy]
+user_name = "legacy"
+api_user  = legacy
+api_key   = 'legacykey'
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := loadCredentialsProfile(path, "legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := credentialsProfile{UserName: "legacy", ApiUser: "legacy", ApiKey: "legacykey"}
+	if *profile != expected {
+		t.Errorf("expected %+v, got %+v", expected, *profile)
+	}
+
+	profile, err = loadCredentialsProfile(path, defaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.ApiKey != "brandkey" || profile.ClientIp != "127.0.0.1" {
+		t.Errorf("unexpected default profile %+v", *profile)
+	}
+
+	if _, err := loadCredentialsProfile(path, "reseller"); !errors.Is(err, errProfileNotFound) || !strings.Contains(err.Error(), "[reseller]") {
+		t.Errorf("expected profile not found error, got %v", err)
+	}
+
+	if _, err := loadCredentialsProfile(filepath.Join(t.TempDir(), "missing"), defaultProfile); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected file not exist error, got %v", err)
+	}
+}
+
+func TestLoadCredentialsProfileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("[default]\napi_token = xxx\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadCredentialsProfile(path, defaultProfile); err == nil || !strings.Contains(err.Error(), "unknown key") {
+		t.Errorf("expected unknown key error, got %v", err)
+	}
+}
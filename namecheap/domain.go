@@ -0,0 +1,81 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = domainType{}
+	_ basetypes.StringValuableWithSemanticEquals = domainValue{}
+)
+
+// domainType is a domain name, which may be given in its Unicode or punycode
+// form.
+type domainType struct {
+	basetypes.StringType
+}
+
+func (t domainType) Equal(o attr.Type) bool {
+	other, ok := o.(domainType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t domainType) String() string {
+	return "domainType"
+}
+
+func (t domainType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return domainValue{StringValue: in}, nil
+}
+
+func (t domainType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	value, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := value.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", value)
+	}
+	return domainValue{StringValue: s}, nil
+}
+
+func (t domainType) ValueType(_ context.Context) attr.Value {
+	return domainValue{}
+}
+
+// domainValue is the value of a domainType.
+type domainValue struct {
+	basetypes.StringValue
+}
+
+func (v domainValue) Equal(o attr.Value) bool {
+	other, ok := o.(domainValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v domainValue) Type(_ context.Context) attr.Type {
+	return domainType{}
+}
+
+// StringSemanticEquals reports whether both values are the same domain, in
+// the same or the other of its Unicode and punycode forms.
+func (v domainValue) StringSemanticEquals(_ context.Context, o basetypes.StringValuable) (bool, diag.Diagnostics) {
+	other, ok := o.(domainValue)
+	if !ok {
+		return false, nil
+	}
+	return asciiDomainOf(v.StringValue) == asciiDomainOf(other.StringValue), nil
+}
@@ -2,22 +2,33 @@ package namecheap
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/idna"
 
 	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
 )
@@ -27,18 +38,45 @@ const (
 	MODE_REACTIVATE string = "reactivate"
 )
 
+const (
+	DELETION_POLICY_ABANDON string = "abandon"
+	DELETION_POLICY_PREVENT string = "prevent"
+	DELETION_POLICY_RELEASE string = "release"
+)
+
+const (
+	DNS_MODE_CUSTOM  string = "custom"
+	DNS_MODE_BASIC   string = "basic"
+	DNS_MODE_PREMIUM string = "premium"
+)
+
 type namecheapDomainResource struct {
-	client *namecheap.Client
+	clients *namecheapClients
+	// client is the client of the account selected by the resource, set by
+	// useAccount at the start of every operation.
+	client *sdk.Client
 }
 
 type namecheapDomainState struct {
-	Domain           types.String  `tfsdk:"domain"`
-	Nameservers      types.List    `tfsdk:"nameservers"`
-	MaxPrice         types.Float64 `tfsdk:"max_price"`
-	MinDaysRemaining types.Int64   `tfsdk:"min_days_remaining"`
-	Years            types.Int64   `tfsdk:"purchase_years"`
-	DomainExpiryDate types.String  `tfsdk:"domain_expiry_date"`
-	RequiredRenew    types.Bool    `tfsdk:"required_renew"`
+	Domain              domainValue      `tfsdk:"domain"`
+	Account             types.String     `tfsdk:"account"`
+	DomainUnicode       types.String     `tfsdk:"domain_unicode"`
+	IdnCode             types.String     `tfsdk:"idn_code"`
+	Nameservers         nameserversValue `tfsdk:"nameservers"`
+	DNSMode             types.String     `tfsdk:"dns_mode"`
+	AutoRenew           types.Bool       `tfsdk:"auto_renew"`
+	DeletionPolicy      types.String     `tfsdk:"deletion_policy"`
+	MaxPrice            types.Float64    `tfsdk:"max_price"`
+	Currency            types.String     `tfsdk:"currency"`
+	AllowPremium        types.Bool       `tfsdk:"allow_premium"`
+	AdoptExisting       types.Bool       `tfsdk:"adopt_existing"`
+	ExtendedAttributes  types.Map        `tfsdk:"extended_attributes"`
+	MinDaysRemaining    types.Int64      `tfsdk:"min_days_remaining"`
+	Years               types.Int64      `tfsdk:"purchase_years"`
+	DomainExpiryDate    types.String     `tfsdk:"domain_expiry_date"`
+	RequiredRenew       types.Bool       `tfsdk:"required_renew"`
+	IsPremium           types.Bool       `tfsdk:"is_premium"`
+	PremiumRenewalPrice types.Float64    `tfsdk:"premium_renewal_price"`
 }
 
 func NewNamecheapDomainResource() resource.Resource {
@@ -54,41 +92,170 @@ func (r *namecheapDomainResource) Metadata(_ context.Context, req resource.Metad
 func (r *namecheapDomainResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manage a domain in NameCheap",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"domain": &schema.StringAttribute{
-				MarkdownDescription: "Domain name to manage in NameCheap",
-				Required:            true,
+				MarkdownDescription: "Domain name to manage in NameCheap. Internationalized domain names may be " +
+					"given in either their Unicode or punycode form, which are treated as the same domain.",
+				Required:   true,
+				CustomType: domainType{},
+				Validators: []validator.String{
+					hostnameValidator{},
+				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						domainRequiresReplace,
+						"Changing the domain forces a replacement, unless only its Unicode or punycode form changes.",
+						"Changing the domain forces a replacement, unless only its Unicode or punycode form changes.",
+					),
+				},
+			},
+			"account": &schema.StringAttribute{
+				MarkdownDescription: "Name of the provider `accounts` entry whose credentials manage the domain. " +
+					"The default is the top-level credentials of the provider. Changing it only switches the " +
+					"credentials, the domain must already be in the new account.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
 				},
 			},
-			"nameservers": &schema.ListAttribute{
-				MarkdownDescription: "Nameservers for the domain",
-				Required:            true,
-				ElementType:         types.StringType,
+			"domain_unicode": &schema.StringAttribute{
+				MarkdownDescription: "The Unicode form of the domain name.",
+				Computed:            true,
+			},
+			"idn_code": &schema.StringAttribute{
+				MarkdownDescription: "The language code of an internationalized domain name, e.g. `GER`, sent when " +
+					"the domain is registered.",
+				Optional: true,
+			},
+			"nameservers": &schema.SetAttribute{
+				MarkdownDescription: "Nameservers for the domain, between 2 and 12 hostnames, compared " +
+					"case-insensitively. Required when `dns_mode` is `custom`. Otherwise NameCheap assigns the " +
+					"nameservers, which are reported here.",
+				CustomType:  newNameserversType(),
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeBetween(2, 12),
+					setvalidator.ValueStringsAre(hostnameValidator{}),
+				},
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"dns_mode": &schema.StringAttribute{
+				MarkdownDescription: "DNS service of the domain, one of `custom` to use `nameservers`, `basic` for " +
+					"NameCheap BasicDNS or `premium` for NameCheap PremiumDNS, which requires an active PremiumDNS " +
+					"subscription, so domains are registered with another mode first. The default is `custom` if " +
+					"`nameservers` is set and `basic` otherwise.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(DNS_MODE_CUSTOM, DNS_MODE_BASIC, DNS_MODE_PREMIUM),
+				},
 			},
 			"max_price": &schema.Float64Attribute{
-				MarkdownDescription: "Maximum price of the purchase domain",
-				Required:            true,
+				MarkdownDescription: "Maximum price of the purchase domain for all `purchase_years`, including ICANN and EAP fees. The " +
+					"comparison is exact to the cent. The value must be greater than 0.",
+				Required: true,
+				Validators: []validator.Float64{
+					positiveFloat64Validator{},
+				},
+			},
+			"currency": &schema.StringAttribute{
+				MarkdownDescription: "Currency of `max_price`. The default is `USD`. The purchase fails if NameCheap " +
+					"quotes the price in a different currency.",
+				Optional: true,
+			},
+			"allow_premium": &schema.BoolAttribute{
+				MarkdownDescription: "Whether a premium domain may be registered, as long as its price is within " +
+					"`max_price`. The default is `false`, which fails the creation of premium domains.",
+				Optional: true,
+			},
+			"adopt_existing": &schema.BoolAttribute{
+				MarkdownDescription: "Whether to manage the domain without purchasing it when it is already " +
+					"registered in the account, applying the configured DNS settings. The default is `false`, " +
+					"which fails the creation of domains that are already registered.",
+				Optional: true,
+			},
+			"extended_attributes": &schema.MapAttribute{
+				MarkdownDescription: "Extended attributes required by the registry of some TLDs, such as " +
+					"`RegistrantNexus` and `RegistrantPurpose` for `.us` domains. They are only sent when " +
+					"the domain is registered, and the attributes known to be required for the TLD are " +
+					"validated at plan time. Names of the core parameters of `namecheap.domains.create`, such " +
+					"as `Years` or `RegistrantEmailAddress`, are rejected.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					extendedAttributesValidator{},
+				},
 			},
 			"min_days_remaining": &schema.Int64Attribute{
 				MarkdownDescription: "The minimum amount of days remaining on the expiration of a domain before a " +
 					"renewal is attempted. The default is `30`. A value of less than `0` means that the domain will " +
 					"never be renewed.",
 				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(30),
 			},
 			"purchase_years": &schema.Int64Attribute{
 				MarkdownDescription: "Number of years to purchase and renew. The default is `1`. The value must greater than 0 and less than or equal to 10",
 				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 10),
+				},
+			},
+			"auto_renew": &schema.BoolAttribute{
+				MarkdownDescription: "Whether NameCheap renews the domain automatically from the account balance, " +
+					"independently of `min_days_remaining`. The NameCheap API cannot change this flag, so when set, " +
+					"planning fails until it is turned on or off in the NameCheap dashboard to match. The flag of " +
+					"domains adopted with `adopt_existing` is only known, and checked, once they are adopted. New " +
+					"registrations start with auto-renew off, so it can only be set to `true` once registered.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deletion_policy": &schema.StringAttribute{
+				MarkdownDescription: "What destroying the resource does, since NameCheap domains cannot be deleted. " +
+					"`abandon` only removes the domain from the state, `prevent` fails the destroy until the policy " +
+					"is changed and applied, and `release` also points the domain back to NameCheap BasicDNS and " +
+					"disables its privacy protection. Since the NameCheap API cannot change auto-renew, `release` " +
+					"fails while the domain still renews automatically. The default is `abandon`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DELETION_POLICY_ABANDON),
+				Validators: []validator.String{
+					stringvalidator.OneOf(DELETION_POLICY_ABANDON, DELETION_POLICY_PREVENT, DELETION_POLICY_RELEASE),
+				},
 			},
 			"domain_expiry_date": &schema.StringAttribute{
-				MarkdownDescription: "The expiry date of the domain, stored in ISO 8601 format (e.g., `2024-12-30T14:59:59Z`). This field is computed automatically based on the domain's expiration date.",
+				MarkdownDescription: "The expiry date of the domain in RFC 3339 format and UTC (e.g., `2024-12-30T14:59:59Z`). This field is computed automatically based on the domain's expiration date.",
 				Computed:            true,
 			},
 			"required_renew": &schema.BoolAttribute{
 				MarkdownDescription: "A boolean flag to keep track of whether domain renewal action is required. ",
 				Computed:            true,
 			},
+			"is_premium": &schema.BoolAttribute{
+				MarkdownDescription: "Whether the domain is a premium domain.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"premium_renewal_price": &schema.Float64Attribute{
+				MarkdownDescription: "The yearly renewal price of a premium domain, as quoted when it was registered. " +
+					"Renewals of premium domains are charged at this price instead of the regular TLD price.",
+				Computed: true,
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -99,16 +266,28 @@ func (r *namecheapDomainResource) Configure(_ context.Context, req resource.Conf
 		// this data available on apply stage
 		return
 	}
-	client, ok := req.ProviderData.(*namecheap.Client)
+	clients, ok := req.ProviderData.(*namecheapClients)
 	if !ok {
-		resp.Diagnostics.AddError("req.ProviderData isn't a namecheap.Client", "")
+		resp.Diagnostics.AddError("req.ProviderData isn't a namecheapClients", "")
 		return
 	}
+	r.clients = clients
+}
+
+// useAccount selects the client of the account for the rest of the operation.
+func (r *namecheapDomainResource) useAccount(account types.String) diag.Diagnostic {
+	client, d := r.clients.clientOf(account)
+	if d != nil {
+		return d
+	}
 	r.client = client
+	return nil
 }
 
 // Create
 func (r *namecheapDomainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	defer explainClientIp(&resp.Diagnostics, r.clients)
+
 	var plan *namecheapDomainState
 	d := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(d...)
@@ -116,49 +295,134 @@ func (r *namecheapDomainResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	domain := plan.Domain.ValueString()
+	if d := r.useAccount(plan.Account); d != nil {
+		resp.Diagnostics.Append(d)
+		return
+	}
+
+	domain := asciiDomainOf(plan.Domain.StringValue)
 	years := plan.Years.ValueInt64()
-	maxprice := plan.MaxPrice.ValueFloat64()
+	maxprice := maxPriceOf(plan)
+	dnsMode := plan.DNSMode.ValueString()
+	// NameCheap's own DNS is used when no nameservers are given.
 	var nameservers string
-	for _, x := range plan.Nameservers.Elements() {
-		nameservers += strings.Trim(x.String(), "\"") + ","
+	if dnsMode == DNS_MODE_CUSTOM {
+		nameservers = strings.Join(plan.Nameservers.hostnames(), ",")
 	}
 
-	createDomain := func() error {
-		d1 := r.createDomain(ctx, domain, strconv.FormatInt(years, 10), nameservers, maxprice)
-		resp.Diagnostics.Append(d1)
-		if resp.Diagnostics.HasError() {
-			return fmt.Errorf("domain creation failed: %v", resp.Diagnostics)
+	allowPremium := plan.AllowPremium.ValueBool()
+	var premiumRenewalPrice *sdk.Money
+
+	extendedAttributes := map[string]string{}
+	resp.Diagnostics.Append(plan.ExtendedAttributes.ElementsAs(ctx, &extendedAttributes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var adopted *namecheap.DomainsGetInfoCommandResponse
+	if plan.AdoptExisting.ValueBool() {
+		if info, err := sdk.DomainsGetInfo(r.client, domain); err == nil {
+			log(ctx, "domain [%s] is already registered in this account, adopting it", domain)
+			adopted = info
 		}
-		return nil
 	}
 
-	reconnectBackoff := backoff.NewExponentialBackOff()
-	reconnectBackoff.MaxElapsedTime = 30 * time.Second
-	err := backoff.Retry(createDomain, reconnectBackoff)
-	if err != nil {
-		resp.Diagnostics.Append(diagnosticErrorOf(err, "domain [%s] creation failed after retries", domain))
+	// PremiumDNS is subscribed to for a domain once it is registered, so a
+	// domain yet to be registered cannot have it. Fail before buying it.
+	if adopted == nil && dnsMode == DNS_MODE_PREMIUM {
+		resp.Diagnostics.AddAttributeError(path.Root("dns_mode"), "PremiumDNS not available",
+			fmt.Sprintf("domain [%s] has no active PremiumDNS subscription before it is registered. Register it "+
+				"with dns_mode basic, and switch to premium once subscribed.", domain))
 		return
 	}
 
+	// The sdk retries failed requests, except for the paid ones, which
+	// are not sent again in case NameCheap has processed them.
+	if adopted == nil {
+		var d1 diag.Diagnostic
+		premiumRenewalPrice, d1 = r.createDomain(ctx, domain, strconv.FormatInt(years, 10), nameservers, plan.IdnCode.ValueString(), extendedAttributes, maxprice, allowPremium)
+		resp.Diagnostics.Append(d1)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	state := namecheapDomainState{
-		Domain:           plan.Domain,
-		Years:            plan.Years,
-		MaxPrice:         plan.MaxPrice,
-		MinDaysRemaining: plan.MinDaysRemaining,
-		Nameservers:      plan.Nameservers,
+		Domain:              plan.Domain,
+		Account:             plan.Account,
+		DomainUnicode:       types.StringValue(unicodeDomainOf(domain)),
+		IdnCode:             plan.IdnCode,
+		Years:               plan.Years,
+		MaxPrice:            plan.MaxPrice,
+		Currency:            plan.Currency,
+		AllowPremium:        plan.AllowPremium,
+		AdoptExisting:       plan.AdoptExisting,
+		ExtendedAttributes:  plan.ExtendedAttributes,
+		MinDaysRemaining:    plan.MinDaysRemaining,
+		Nameservers:         plan.Nameservers,
+		DNSMode:             plan.DNSMode,
+		DeletionPolicy:      plan.DeletionPolicy,
+		IsPremium:           types.BoolValue(premiumRenewalPrice != nil),
+		PremiumRenewalPrice: types.Float64Null(),
+	}
+	if premiumRenewalPrice != nil {
+		state.PremiumRenewalPrice = types.Float64Value(premiumRenewalPrice.Amount.InexactFloat64())
+	}
+
+	// The domain is in the account from here on. When a later step fails,
+	// the state is still saved, tainted by Terraform, so that a domain just
+	// bought or adopted is not lost from the state.
+	fail := func(d diag.Diagnostic) {
+		resp.Diagnostics.Append(d)
+		if state.Nameservers.IsUnknown() {
+			state.Nameservers = newNameserversNull()
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	}
+
+	if adopted != nil {
+		if isPremium := adopted.DomainDNSGetListResult.IsPremium; isPremium != nil {
+			state.IsPremium = types.BoolValue(*isPremium)
+		}
+		nameservers, d3 := r.reconcileDNS(domain, dnsMode, plan.Nameservers)
+		if d3 != nil {
+			fail(d3)
+			return
+		}
+		state.Nameservers = nameservers
+	} else if dnsMode != DNS_MODE_CUSTOM {
+		nameservers, d3 := r.setDNSMode(domain, dnsMode, nil)
+		if d3 != nil {
+			fail(d3)
+			return
+		}
+		state.Nameservers = nameservers
+	}
+
+	entry, d4 := r.getListEntry(domain)
+	if d4 != nil {
+		fail(d4)
+		return
 	}
+	state.AutoRenew = types.BoolValue(entry.AutoRenew != nil && *entry.AutoRenew)
 
 	// Compute `domainExpiryDate` and `domainExpiryRemainingDays` to get the expiration date and
 	// remaining active days of the domain.
-	domainExpiryDate, _err := r.getDomainExpiryDate(plan.Domain.ValueString())
+	domainExpiryDate, _err := r.getDomainExpiryDate(domain)
 	if _err != nil {
-		resp.Diagnostics.Append(_err)
+		fail(_err)
 		return
 	}
-	state.DomainExpiryDate = types.StringValue(domainExpiryDate.Format("2006-01-02T15:04:05Z"))
+	state.DomainExpiryDate = types.StringValue(formatExpiryDate(domainExpiryDate))
 	state.RequiredRenew = types.BoolValue(false)
 
+	// An adopted domain may renew automatically already, which is only
+	// known now.
+	if !plan.AutoRenew.IsUnknown() && !plan.AutoRenew.IsNull() && plan.AutoRenew.ValueBool() != state.AutoRenew.ValueBool() {
+		fail(autoRenewMismatchOf(domain, plan.AutoRenew.ValueBool()))
+		return
+	}
+
 	d2 := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(d2...)
 	if resp.Diagnostics.HasError() {
@@ -168,6 +432,8 @@ func (r *namecheapDomainResource) Create(ctx context.Context, req resource.Creat
 
 // Read
 func (r *namecheapDomainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	defer explainClientIp(&resp.Diagnostics, r.clients)
+
 	var state *namecheapDomainState
 	d := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(d...)
@@ -175,8 +441,13 @@ func (r *namecheapDomainResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
-	domain := state.Domain.ValueString()
-	getResp, err := r.client.Domains.GetInfo(domain)
+	if d := r.useAccount(state.Account); d != nil {
+		resp.Diagnostics.Append(d)
+		return
+	}
+
+	domain := asciiDomainOf(state.Domain.StringValue)
+	getResp, err := sdk.DomainsGetInfo(r.client, domain)
 	if err != nil {
 		if strings.Contains(err.Error(), "Domain is invalid") {
 			resp.State.RemoveResource(ctx)
@@ -186,18 +457,28 @@ func (r *namecheapDomainResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
-	nameserver := []attr.Value{}
-	for _, x := range *getResp.DomainDNSGetListResult.DnsDetails.Nameservers {
-		nameserver = append(nameserver, types.StringValue(x))
+	state.DNSMode, state.Nameservers = dnsOf(getResp)
+	if state.DeletionPolicy.IsNull() {
+		state.DeletionPolicy = types.StringValue(DELETION_POLICY_ABANDON)
+	}
+
+	entry, _err := r.getListEntry(domain)
+	if _err != nil {
+		resp.Diagnostics.Append(_err)
+		return
+	}
+	state.AutoRenew = types.BoolValue(entry.AutoRenew != nil && *entry.AutoRenew)
+	state.DomainUnicode = types.StringValue(unicodeDomainOf(domain))
+	if getResp.DomainDNSGetListResult.IsPremium != nil {
+		state.IsPremium = types.BoolValue(*getResp.DomainDNSGetListResult.IsPremium)
 	}
-	state.Nameservers = types.ListValueMust(types.StringType, nameserver)
 
 	domainExpiryDate, _err := r.getDomainExpiryDate(domain)
 	if _err != nil {
 		resp.Diagnostics.Append(_err)
 		return
 	}
-	state.DomainExpiryDate = types.StringValue(domainExpiryDate.Format("2006-01-02T15:04:05Z"))
+	state.DomainExpiryDate = types.StringValue(formatExpiryDate(domainExpiryDate))
 
 	d1 := resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(d1...)
@@ -208,6 +489,8 @@ func (r *namecheapDomainResource) Read(ctx context.Context, req resource.ReadReq
 
 // Update namecheap_domain resource and sets the updated Terraform state on success.
 func (r *namecheapDomainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	defer explainClientIp(&resp.Diagnostics, r.clients)
+
 	var plan *namecheapDomainState
 	d := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(d...)
@@ -215,18 +498,43 @@ func (r *namecheapDomainResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
+	var prior *namecheapDomainState
+	d = req.State.Get(ctx, &prior)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d := r.useAccount(plan.Account); d != nil {
+		resp.Diagnostics.Append(d)
+		return
+	}
+
+	domain := asciiDomainOf(plan.Domain.StringValue)
+
 	// Set state
 	state := namecheapDomainState{
-		Domain:           plan.Domain,
-		Years:            plan.Years,
-		MaxPrice:         plan.MaxPrice,
-		MinDaysRemaining: plan.MinDaysRemaining,
-		Nameservers:      plan.Nameservers,
+		Domain:              plan.Domain,
+		Account:             plan.Account,
+		DomainUnicode:       types.StringValue(unicodeDomainOf(domain)),
+		IdnCode:             plan.IdnCode,
+		Years:               plan.Years,
+		MaxPrice:            plan.MaxPrice,
+		Currency:            plan.Currency,
+		AllowPremium:        plan.AllowPremium,
+		AdoptExisting:       plan.AdoptExisting,
+		ExtendedAttributes:  plan.ExtendedAttributes,
+		MinDaysRemaining:    plan.MinDaysRemaining,
+		Nameservers:         plan.Nameservers,
+		DNSMode:             plan.DNSMode,
+		DeletionPolicy:      plan.DeletionPolicy,
+		IsPremium:           prior.IsPremium,
+		PremiumRenewalPrice: prior.PremiumRenewalPrice,
 	}
 
 	// Compute `domainExpiryDate` and `domainExpiryRemainingDays` to get the expiration date and
 	// remaining active days of the domain.
-	domainExpiryDate, err := r.getDomainExpiryDate(plan.Domain.ValueString())
+	domainExpiryDate, err := r.getDomainExpiryDate(domain)
 	if err != nil {
 		resp.Diagnostics.Append(err)
 		return
@@ -237,7 +545,6 @@ func (r *namecheapDomainResource) Update(ctx context.Context, req resource.Updat
 
 	// Attempt to renew / reactivate domain if the `DomainRemainingDays` is lesser or equal to `MinDaysRemaining`
 	if domainExpiryRemainingDays <= plan.MinDaysRemaining.ValueInt64() {
-		domain := plan.Domain.ValueString()
 		renewYear := plan.Years.ValueInt64()
 
 		newMode, diag := r.calculateMode(domain)
@@ -265,7 +572,7 @@ func (r *namecheapDomainResource) Update(ctx context.Context, req resource.Updat
 		}
 
 		// Update and refresh expiration details after domain renewal / reactivate is done.
-		domainExpiryDate, err = r.getDomainExpiryDate(plan.Domain.ValueString())
+		domainExpiryDate, err = r.getDomainExpiryDate(domain)
 		if err != nil {
 			resp.Diagnostics.Append(err)
 			return
@@ -273,18 +580,22 @@ func (r *namecheapDomainResource) Update(ctx context.Context, req resource.Updat
 	}
 
 	// Update and refresh state for attributes `domainExpiryDate` & `domainExpirationDays`
-	state.DomainExpiryDate = types.StringValue(domainExpiryDate.Format("2006-01-02T15:04:05Z"))
+	state.DomainExpiryDate = types.StringValue(formatExpiryDate(domainExpiryDate))
 	state.RequiredRenew = types.BoolValue(false)
 
 	// Configure nameservers
-	var nameservers []string
-	for _, x := range plan.Nameservers.Elements() {
-		nameservers = append(nameservers, strings.Trim(x.String(), "\""))
+	state.Nameservers, err = r.reconcileDNS(domain, plan.DNSMode.ValueString(), plan.Nameservers)
+	if err != nil {
+		resp.Diagnostics.Append(err)
+		return
 	}
-	_, _err := r.client.DomainsDNS.SetCustom(plan.Domain.ValueString(), nameservers)
-	if _err != nil {
-		resp.Diagnostics.AddError("Set nameserver failed error ", _err.Error())
+
+	entry, err := r.getListEntry(domain)
+	if err != nil {
+		resp.Diagnostics.Append(err)
+		return
 	}
+	state.AutoRenew = types.BoolValue(entry.AutoRenew != nil && *entry.AutoRenew)
 
 	setStateDiags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(setStateDiags...)
@@ -295,6 +606,8 @@ func (r *namecheapDomainResource) Update(ctx context.Context, req resource.Updat
 
 // Delete namecheap_domain resource and removes the Terraform state on success.
 func (r *namecheapDomainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	defer explainClientIp(&resp.Diagnostics, r.clients)
+
 	var state *namecheapDomainState
 	getStateDiags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(getStateDiags...)
@@ -302,16 +615,143 @@ func (r *namecheapDomainResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	domain := state.Domain.ValueString()
+	domain := asciiDomainOf(state.Domain.StringValue)
+
+	switch state.DeletionPolicy.ValueString() {
+	case DELETION_POLICY_PREVENT:
+		resp.Diagnostics.AddError("Domain deletion prevented",
+			fmt.Sprintf("The deletion_policy of domain [%s] is prevent. Set it to abandon or release and apply "+
+				"before destroying the domain.", domain))
+		return
+	case DELETION_POLICY_RELEASE:
+		if d := r.useAccount(state.Account); d != nil {
+			resp.Diagnostics.Append(d)
+			return
+		}
+		resp.Diagnostics.Append(r.releaseDomain(ctx, domain, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Since domain can not be deleted in NameCheap, so we do nothing here but give a warning
 	msg := fmt.Sprintf("Since domain can not be deleted in NameCheap, [%s] still exist actually", domain)
 	tflog.Warn(ctx, msg)
+	resp.Diagnostics.AddWarning("Domain is still registered",
+		fmt.Sprintf("Domains cannot be deleted in NameCheap. [%s] remains registered in the account until it "+
+			"expires, and is no longer managed by Terraform.", domain))
 }
 
+// releaseDomain undoes the settings of a domain before it is dropped from the
+// state: it points the domain back to NameCheap BasicDNS and disables its
+// privacy protection. Auto-renew must be off already, since the NameCheap API
+// cannot turn it off, or the domain would keep being renewed unmanaged.
+func (r *namecheapDomainResource) releaseDomain(ctx context.Context, domain string, state *namecheapDomainState) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Checked before anything else, so a failed release leaves the domain
+	// unchanged.
+	entry, d := r.getListEntry(domain)
+	if d != nil {
+		diags.Append(d)
+		return diags
+	}
+	if entry.AutoRenew != nil && *entry.AutoRenew {
+		diags.AddError("Auto-renew is still on",
+			fmt.Sprintf("The NameCheap API cannot change auto-renew. Turn it off for domain [%s] in the NameCheap "+
+				"dashboard before destroying it with deletion_policy release, or set deletion_policy to abandon.", domain))
+		return diags
+	}
+
+	if state.DNSMode.ValueString() == DNS_MODE_CUSTOM {
+		log(ctx, "releasing custom nameservers of domain [%s]", domain)
+		if _, err := sdk.DomainsDNSSetDefault(r.client, domain); err != nil {
+			diags.Append(diagnosticErrorOf(err, "setting default DNS of domain [%s] failed", domain))
+			return diags
+		}
+	}
+
+	whoisguard, err := sdk.WhoisguardOf(r.client, domain)
+	if err != nil {
+		diags.Append(diagnosticErrorOf(err, "get privacy protection of domain [%s] failed", domain))
+		return diags
+	}
+	if whoisguard != nil && whoisguard.Enabled() {
+		log(ctx, "disabling privacy protection of domain [%s]", domain)
+		if _, err := sdk.WhoisguardDisable(r.client, whoisguard.ID); err != nil {
+			diags.Append(diagnosticErrorOf(err, "disabling privacy protection of domain [%s] failed", domain))
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// ImportState accepts the domain name, "domain,years,min_days" or
+// "domain,years,min_days,max_price" as identifier, optionally prefixed with
+// "account/" to import the domain from one of the provider accounts. The
+// remaining attributes are populated by Read, except max_price, which NameCheap
+// does not know and is left null unless given.
 func (r *namecheapDomainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import RecordId and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("domain"), req, resp)
+	id := req.ID
+	account := types.StringNull()
+	if i := strings.Index(id, "/"); i >= 0 {
+		account = types.StringValue(id[:i])
+		id = id[i+1:]
+		if account.ValueString() == "" {
+			resp.Diagnostics.AddError("Unexpected import identifier",
+				fmt.Sprintf("Expected an account name before / in import identifier, got: %q", req.ID))
+			return
+		}
+	}
+
+	parts := strings.Split(id, ",")
+	if len(parts) != 1 && len(parts) != 3 && len(parts) != 4 {
+		resp.Diagnostics.AddError("Unexpected import identifier",
+			fmt.Sprintf("Expected an import identifier of the form [account/]domain, [account/]domain,years,min_days "+
+				"or [account/]domain,years,min_days,max_price, got: %q", req.ID))
+		return
+	}
+
+	domain := strings.TrimSpace(parts[0])
+	if _, err := domainToASCII(domain); err != nil {
+		resp.Diagnostics.AddError("Invalid domain name", err.Error())
+		return
+	}
+
+	// Same as the defaults of the schema.
+	years, minDays := int64(1), int64(30)
+	maxPrice := types.Float64Null()
+	if len(parts) >= 3 {
+		var err error
+		if years, err = strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64); err != nil || years < 1 || years > 10 {
+			resp.Diagnostics.AddError("Unexpected import identifier",
+				fmt.Sprintf("Expected years between 1 and 10 in import identifier, got: %q", parts[1]))
+			return
+		}
+		if minDays, err = strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64); err != nil {
+			resp.Diagnostics.AddError("Unexpected import identifier",
+				fmt.Sprintf("Expected a number of min_days in import identifier, got: %q", parts[2]))
+			return
+		}
+	}
+	if len(parts) == 4 {
+		price, err := strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+		if err != nil || price <= 0 {
+			resp.Diagnostics.AddError("Unexpected import identifier",
+				fmt.Sprintf("Expected a max_price greater than 0 in import identifier, got: %q", parts[3]))
+			return
+		}
+		maxPrice = types.Float64Value(price)
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("account"), account)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("purchase_years"), years)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("min_days_remaining"), minDays)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("max_price"), maxPrice)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("required_renew"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_policy"), DELETION_POLICY_ABANDON)...)
 }
 
 func (r *namecheapDomainResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
@@ -322,6 +762,60 @@ func (r *namecheapDomainResource) ModifyPlan(ctx context.Context, req resource.M
 		if resp.Diagnostics.HasError() {
 			return
 		}
+
+		// Report unknown accounts before anything is applied. The clients
+		// are only configured once the provider configuration is known.
+		if r.clients != nil && !plan.Account.IsUnknown() {
+			if _, d := r.clients.clientOf(plan.Account); d != nil {
+				resp.Diagnostics.Append(d)
+				return
+			}
+		}
+
+		r.planDNSMode(ctx, req, resp)
+		r.planAutoRenew(ctx, req, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if plan.Domain.IsUnknown() {
+			resp.Plan.SetAttribute(ctx, path.Root("domain_unicode"), types.StringUnknown())
+			return
+		}
+		domain, err := domainToASCII(plan.Domain.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("domain"), "Invalid domain name", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("domain_unicode"), types.StringValue(unicodeDomainOf(domain)))...)
+
+		// Registration fails without the extended attributes required by the
+		// registry, so report them before anything is purchased. They are
+		// only sent on registration, so existing domains are not checked.
+		if req.State.Raw.IsNull() && !plan.ExtendedAttributes.IsUnknown() {
+			values := map[string]types.String{}
+			resp.Diagnostics.Append(plan.ExtendedAttributes.ElementsAs(ctx, &values, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			extendedAttributes := map[string]string{}
+			for name, value := range values {
+				if value.IsUnknown() {
+					// Known only after apply, assume it will be set.
+					extendedAttributes[name] = value.String()
+				} else {
+					extendedAttributes[name] = value.ValueString()
+				}
+			}
+			if missing := sdk.MissingExtendedAttributes(domain, extendedAttributes); len(missing) > 0 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("extended_attributes"),
+					"Missing extended attributes",
+					fmt.Sprintf("domain [%s] requires the extended attributes [%s]", plan.Domain.ValueString(), strings.Join(missing, ", ")),
+				)
+				return
+			}
+		}
 	}
 
 	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
@@ -329,7 +823,7 @@ func (r *namecheapDomainResource) ModifyPlan(ctx context.Context, req resource.M
 	}
 
 	var plan namecheapDomainState
-	diags := req.Plan.Get(ctx, &plan)
+	diags := resp.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 
 	requiresRenew, err := isDomainRequiredRenew(plan.MinDaysRemaining.ValueInt64(), plan.DomainExpiryDate.ValueString())
@@ -356,95 +850,297 @@ func (r *namecheapDomainResource) ModifyPlan(ctx context.Context, req resource.M
 	resp.Plan.Set(ctx, plan)
 }
 
-func (r *namecheapDomainResource) calculateMode(domain string) (string, diag.Diagnostic) {
-	res, err := r.client.Domains.GetList(&namecheap.DomainsGetListArgs{
-		SearchTerm: &domain,
-	})
+// planDNSMode plans dns_mode from the configuration, and leaves the
+// nameservers to be assigned by NameCheap when switching to its own DNS.
+func (r *namecheapDomainResource) planDNSMode(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var config namecheapDomainState
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.DNSMode.IsUnknown() {
+		return
+	}
+
+	mode := config.DNSMode.ValueString()
+	if config.DNSMode.IsNull() {
+		mode = DNS_MODE_CUSTOM
+		if config.Nameservers.IsNull() {
+			mode = DNS_MODE_BASIC
+		}
+	}
+
+	if mode == DNS_MODE_CUSTOM && config.Nameservers.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("nameservers"), "Missing nameservers",
+			"nameservers must be set when dns_mode is custom")
+		return
+	}
+	if mode != DNS_MODE_CUSTOM && !config.Nameservers.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("nameservers"), "Conflicting nameservers",
+			fmt.Sprintf("nameservers can only be set when dns_mode is custom, not %s", mode))
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("dns_mode"), types.StringValue(mode))...)
+
+	if mode != DNS_MODE_CUSTOM {
+		var priorMode types.String
+		if !req.State.Raw.IsNull() {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("dns_mode"), &priorMode)...)
+		}
+		if priorMode.ValueString() != mode {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("nameservers"), newNameserversUnknown())...)
+		}
+	}
+}
+
+// planAutoRenew fails the plan when auto_renew is configured differently from
+// the flag NameCheap reports, since the API has no command to change it.
+func (r *namecheapDomainResource) planAutoRenew(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var autoRenew, priorAutoRenew, adoptExisting types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("auto_renew"), &autoRenew)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("adopt_existing"), &adoptExisting)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("auto_renew"), &priorAutoRenew)...)
+	}
+	if resp.Diagnostics.HasError() || autoRenew.IsNull() || autoRenew.IsUnknown() {
+		return
+	}
+
+	// A domain that may be adopted is checked by Create instead, once its
+	// flag is known.
+	if req.State.Raw.IsNull() && (adoptExisting.IsUnknown() || adoptExisting.ValueBool()) {
+		return
+	}
+	if autoRenew.ValueBool() == priorAutoRenew.ValueBool() {
+		return
+	}
+
+	var domain domainValue
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("domain"), &domain)...)
+
+	// Without prior state the domain is yet to be registered, and new
+	// registrations don't renew automatically.
+	if req.State.Raw.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("auto_renew"), "Auto-renew is off for new registrations",
+			fmt.Sprintf("New registrations start with auto-renew off and the NameCheap API cannot change it. Register "+
+				"domain [%s] without auto_renew, then enable auto-renew in the NameCheap dashboard and set auto_renew "+
+				"to true.", domain.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(autoRenewMismatchOf(domain.ValueString(), autoRenew.ValueBool()))
+}
+
+// autoRenewMismatchOf reports that auto_renew is configured as autoRenew but
+// NameCheap reports otherwise for the domain.
+func autoRenewMismatchOf(domain string, autoRenew bool) diag.Diagnostic {
+	onOff := "off"
+	if autoRenew {
+		onOff = "on"
+	}
+	return diag.NewAttributeErrorDiagnostic(path.Root("auto_renew"), "Auto-renew cannot be changed",
+		fmt.Sprintf("The NameCheap API cannot change auto-renew, turn it %s for domain [%s] in the NameCheap "+
+			"dashboard or remove auto_renew from the configuration.", onOff, domain))
+}
+
+// reconcileDNS applies the DNS mode and nameservers, only when they differ
+// from what NameCheap reports, and returns the nameservers to store.
+func (r *namecheapDomainResource) reconcileDNS(domain string, mode string, nameservers nameserversValue) (nameserversValue, diag.Diagnostic) {
+	info, err := sdk.DomainsGetInfo(r.client, domain)
+	if err != nil {
+		return nameserversValue{}, diagnosticErrorOf(err, "get domain [%s] info failed", domain)
+	}
+
+	currentMode, currentNameservers := dnsOf(info)
+	if currentMode.ValueString() != mode ||
+		(mode == DNS_MODE_CUSTOM && !sameNameservers(nameservers.hostnames(), currentNameservers.hostnames())) {
+		var d diag.Diagnostic
+		if currentNameservers, d = r.setDNSMode(domain, mode, nameservers.hostnames()); d != nil {
+			return nameserversValue{}, d
+		}
+	}
+
+	if mode == DNS_MODE_CUSTOM {
+		return nameservers, nil
+	}
+	return currentNameservers, nil
+}
+
+// setDNSMode points the domain to the given nameservers, or to NameCheap's
+// own DNS, and returns the nameservers the domain uses afterwards.
+func (r *namecheapDomainResource) setDNSMode(domain string, mode string, nameservers []string) (nameserversValue, diag.Diagnostic) {
+	if mode == DNS_MODE_CUSTOM {
+		if _, err := sdk.DomainsDNSSetCustom(r.client, domain, nameservers); err != nil {
+			return nameserversValue{}, diagnosticErrorOf(err, "setting nameservers of domain [%s] failed", domain)
+		}
+		return newNameserversValue(nameservers), nil
+	}
+
+	if mode == DNS_MODE_PREMIUM {
+		info, err := sdk.DomainsGetInfo(r.client, domain)
+		if err != nil {
+			return nameserversValue{}, diagnosticErrorOf(err, "get domain [%s] info failed", domain)
+		}
+		subscription := info.DomainDNSGetListResult.PremiumDnsSubscription
+		if subscription == nil || subscription.IsActive == nil || !*subscription.IsActive {
+			return nameserversValue{}, diagnosticErrorOf(nil, "domain [%s] has no active PremiumDNS subscription", domain)
+		}
+	}
+
+	if _, err := sdk.DomainsDNSSetDefault(r.client, domain); err != nil {
+		return nameserversValue{}, diagnosticErrorOf(err, "setting default DNS of domain [%s] failed", domain)
+	}
+	info, err := sdk.DomainsGetInfo(r.client, domain)
 	if err != nil {
-		return "", diagnosticErrorOf(err, "domain [%s] doesn't exist", domain)
+		return nameserversValue{}, diagnosticErrorOf(err, "get domain [%s] info failed", domain)
+	}
+	// NameCheap chooses between BasicDNS and PremiumDNS itself.
+	dnsMode, dnsNameservers := dnsOf(info)
+	if dnsMode.ValueString() != mode {
+		return nameserversValue{}, diagnosticErrorOf(nil, "domain [%s] uses %s DNS after setting NameCheap's DNS, switch it "+
+			"to %s DNS in the NameCheap dashboard or set dns_mode to %s", domain, dnsMode.ValueString(), mode, dnsMode.ValueString())
 	}
+	return dnsNameservers, nil
+}
 
-	respName := *((*res.Domains)[0].Name)
-	if respName != domain {
-		return "", diagnosticErrorOf(nil, "domain [%s] doesn't exist", domain)
+func (r *namecheapDomainResource) calculateMode(domain string) (string, diag.Diagnostic) {
+	entry, d := r.getListEntry(domain)
+	if d != nil {
+		return "", d
 	}
 
-	isExpired := *((*res.Domains)[0].IsExpired)
-	if isExpired {
+	if isExpired(entry, time.Now()) {
 		return MODE_REACTIVATE, nil
 	}
 
 	return MODE_RENEW, nil
 }
 
-func (r *namecheapDomainResource) createDomain(ctx context.Context, domain string, years string, nameservers string, maxprice float64) diag.Diagnostic {
+// isExpired reports whether the listed domain has expired. NameCheap may
+// leave out IsExpired, in which case the expiry date is compared with now,
+// and a domain with neither is taken as not expired.
+func isExpired(entry *namecheap.Domain, now time.Time) bool {
+	if entry.IsExpired != nil {
+		return *entry.IsExpired
+	}
+	return entry.Expires != nil && entry.Expires.Before(now)
+}
+
+// getListEntry returns the domain as listed by namecheap.domains.getList.
+func (r *namecheapDomainResource) getListEntry(domain string) (*namecheap.Domain, diag.Diagnostic) {
+	res, err := sdk.DomainsGetList(r.client, domain)
+	if err != nil {
+		return nil, diagnosticErrorOf(err, "domain [%s] doesn't exist", domain)
+	}
+
+	// The search term also matches domains containing the name.
+	if res.Domains != nil {
+		for _, entry := range *res.Domains {
+			if entry.Name != nil && strings.EqualFold(*entry.Name, domain) {
+				return &entry, nil
+			}
+		}
+	}
+	return nil, diagnosticErrorOf(nil, "domain [%s] doesn't exist", domain)
+}
+
+// createDomain registers the domain if it is available within maxprice. For
+// premium domains it returns the quoted yearly renewal price.
+func (r *namecheapDomainResource) createDomain(ctx context.Context, domain string, years string, nameservers string, idnCode string, extendedAttributes map[string]string, maxprice sdk.Money, allowPremium bool) (*sdk.Money, diag.Diagnostic) {
 	client := r.client
 	// Get domain info
-	if _, err := client.Domains.GetInfo(domain); err == nil {
-		return diagnosticErrorOf(nil, "domain [%s] has been created in this account", domain)
+	if _, err := sdk.DomainsGetInfo(client, domain); err == nil {
+		return nil, diagnosticErrorOf(nil, "domain [%s] has been created in this account, set adopt_existing to manage it", domain)
 	}
 
 	// else, if domain does not exist, check for pricing then create
 	resp, err := sdk.DomainsAvailable(client, domain)
 	if err == nil && resp.Result.Available {
 		// Check domain price before proceed
-		var price float64
+		var price sdk.Money
 
 		// Check if the domain is a premium domain
-		if resp.Result.Price != "0" {
-			price, err = strconv.ParseFloat(resp.Result.Price, 32)
+		if resp.Result.Premium() {
+			n, err := strconv.Atoi(years)
 			if err != nil {
-				return diagnosticErrorOf(err, "get domain price failed: %s", domain)
+				return nil, diagnosticErrorOf(err, "invalid purchase years [%s]", years)
+			}
+			price = resp.Result.PremiumPrice(n)
+			if !allowPremium {
+				return nil, diagnosticErrorOf(nil, "domain [%s] is a premium domain priced at [%s], set allow_premium to register it", domain, price)
 			}
 		} else { //Do a normal price query on the target TLD
 			priceResp, err := sdk.UserGetPricing(client, "register", domain)
-			if err == nil {
-				for _, s := range priceResp.Result.ProductCategory.Price {
-					if s.Duration == years {
-						if price, err = strconv.ParseFloat(s.Price, 32); err != nil {
-							return diagnosticErrorOf(err, "get domain price failed: %s", domain)
-						}
+			if err != nil {
+				return nil, diagnosticErrorOf(err, "get domain price failed: %s", domain)
+			}
+			found := false
+			for _, s := range priceResp.Result.ProductCategory.Price {
+				if s.Duration == years {
+					if price, err = s.Total(); err != nil {
+						return nil, diagnosticErrorOf(err, "get domain price failed: %s", domain)
 					}
+					found = true
 				}
-			} else {
-				return diagnosticErrorOf(err, "get domain price failed: %s", domain)
+			}
+			if !found {
+				return nil, diagnosticErrorOf(nil, "get domain price failed: %s has no price for %s years", domain, years)
+			}
+			if price, err = price.Add(resp.Result.EarlyAccessFee()); err != nil {
+				return nil, diagnosticErrorOf(err, "get domain price failed: %s", domain)
 			}
 		}
 
-		if price <= maxprice {
+		cmp, err := price.Cmp(maxprice)
+		if err != nil {
+			return nil, diagnosticErrorOf(err, "domain [%s] is priced in %s, but max_price is in %s", domain, price.Currency, maxprice.Currency)
+		}
+
+		if cmp <= 0 {
 			// no err, price ok and available, create
 			log(ctx, "Domain [%s] is available, Creating...", domain)
 
 			r, err := r.getUserAccountContact()
 			if err != nil {
 				log(ctx, "get user contacts failed: %s", err.Error())
-				return diagnosticErrorOf(err, "get user contacts failed: %s", domain)
+				return nil, diagnosticErrorOf(err, "get user contacts failed: %s", domain)
 			}
 
-			_, err = sdk.DomainsCreate(client, domain, years, nameservers, r)
+			_, err = sdk.DomainsCreate(client, domain, years, nameservers, r, &sdk.DomainsCreateOptions{
+				IdnCode:            idnCode,
+				ExtendedAttributes: extendedAttributes,
+				Pricing:            resp.Result.CreatePricing(),
+			})
+			if errors.Is(err, sdk.ErrMaybeProcessed) {
+				log(ctx, "create domain [%s] may have failed: %s", domain, err.Error())
+				return nil, diagnosticErrorOf(err, "create domain [%s] may have succeeded, set adopt_existing to manage it if it is in the account", domain)
+			}
 			if err != nil {
 				log(ctx, "create domain [%s] failed: %s", domain, err.Error())
-				return diagnosticErrorOf(err, "create domain [%s] failed", domain)
+				return nil, diagnosticErrorOf(err, "create domain [%s] failed", domain)
 			}
 		} else {
 			log(ctx, "domain [%s] is overprice, exiting!", domain)
-			return diagnosticErrorOf(err, "domain [%s] is overprice [%f], you need to change to another domain", domain, price)
+			return nil, diagnosticErrorOf(err, "domain [%s] is overprice [%s], you need to change to another domain", domain, price)
 		}
 	} else {
 		log(ctx, "domain [%s] is not available, exiting!", domain)
-		return diagnosticErrorOf(err, "domain [%s] is not available to register, you need to change to another domain", domain)
+		return nil, diagnosticErrorOf(err, "domain [%s] is not available to register, you need to change to another domain", domain)
 	}
 
-	return nil
+	if resp.Result.Premium() {
+		renewalPrice := resp.Result.PremiumRenewalPrice()
+		return &renewalPrice, nil
+	}
+	return nil, nil
 }
 
 func (r *namecheapDomainResource) renewDomain(ctx context.Context, domain string, years string) diag.Diagnostic {
 	client := r.client
 	resp, err := sdk.DomainsRenew(client, domain, years)
 
+	if errors.Is(err, sdk.ErrMaybeProcessed) {
+		log(ctx, "renew domain [%s] may have failed: %s", domain, err.Error())
+		return diagnosticErrorOf(err, "renew domain [%s] may have succeeded, check its expiry date in NameCheap before applying again", domain)
+	}
 	if err != nil || !resp.Result.Renew {
 		log(ctx, "renew domain %s failed, exit", domain)
-		log(ctx, "reason: %s", err.Error())
 		return diagnosticErrorOf(err, "renew domain [%s] failed", domain)
 	}
 
@@ -456,8 +1152,12 @@ func (r *namecheapDomainResource) reactivateDomain(ctx context.Context, domain s
 	client := r.client
 	resp, err := sdk.DomainsReactivate(client, domain, years)
 
+	if errors.Is(err, sdk.ErrMaybeProcessed) {
+		log(ctx, "reactivate domain [%s] may have failed: %s", domain, err.Error())
+		return diagnosticErrorOf(err, "reactivate domain [%s] may have succeeded, check its expiry date in NameCheap before applying again", domain)
+	}
 	if err != nil || !resp.Result.IsSuccess {
-		log(ctx, "reactivate domain %s failed: %s", domain, err.Error())
+		log(ctx, "reactivate domain %s failed", domain)
 		return diagnosticErrorOf(err, "reactivate domain [%s] failed", domain)
 	}
 
@@ -489,26 +1189,26 @@ func (r *namecheapDomainResource) getUserAccountContact() (*sdk.UserAddrGetInfoC
 
 func (r *namecheapDomainResource) getDomainExpiryDate(domain string) (time.Time, diag.Diagnostic) {
 	var domainExpiryDate time.Time
-	var err error
 
 	getDomainExpiryInfo := func() error {
-		res, err := r.client.Domains.GetList(&namecheap.DomainsGetListArgs{
-			SearchTerm: &domain,
-		})
-		if err != nil {
-			return fmt.Errorf("domain [%s] doesn't exist: %v", domain, err)
+		entry, d := r.getListEntry(domain)
+		if d != nil {
+			return errors.New(strings.TrimSpace(d.Summary() + " " + d.Detail()))
+		}
+		if entry.Expires == nil {
+			return fmt.Errorf("domain [%s] has no expiry date", domain)
 		}
 
-		domainExpiryDate = (*res.Domains)[0].Expires.Time
+		domainExpiryDate = entry.Expires.Time
 
 		return nil
 	}
 
 	reconnectBackoff := backoff.NewExponentialBackOff()
 	reconnectBackoff.MaxElapsedTime = 30 * time.Second
-	_err := backoff.Retry(getDomainExpiryInfo, reconnectBackoff)
+	err := backoff.Retry(getDomainExpiryInfo, reconnectBackoff)
 
-	if _err != nil {
+	if err != nil {
 		return time.Time{}, diagnosticErrorOf(err, "failed to fetch domain expiry for [%s] after retries", domain)
 	}
 
@@ -524,7 +1224,7 @@ func calcDomainRemainingDays(expiryDate time.Time) int64 {
 
 // Function to determine when the domain requires renewal action
 func isDomainRequiredRenew(minDaysremaining int64, domainExpiry string) (bool, error) {
-	domainExpiryDate, err := time.Parse("2006-01-02T15:04:05Z", domainExpiry)
+	domainExpiryDate, err := time.Parse(time.RFC3339, domainExpiry)
 	if err != nil {
 		return false, err
 	}
@@ -534,6 +1234,81 @@ func isDomainRequiredRenew(minDaysremaining int64, domainExpiry string) (bool, e
 	return (domainRemainingDays <= minDaysremaining), err
 }
 
+// formatExpiryDate returns the expiry date as stored in the state.
+func formatExpiryDate(expiry time.Time) string {
+	return expiry.UTC().Format(time.RFC3339)
+}
+
+// dnsOf returns the DNS mode and nameservers reported by
+// namecheap.domains.getInfo. The mode follows the ProviderType of the DNS
+// details: CUSTOM nameservers, PremiumDNS or else BasicDNS, whatever the
+// PremiumDNS subscription.
+func dnsOf(info *namecheap.DomainsGetInfoCommandResponse) (types.String, nameserversValue) {
+	result := info.DomainDNSGetListResult
+
+	nameservers := []string{}
+	mode := DNS_MODE_BASIC
+	if details := result.DnsDetails; details != nil {
+		if details.Nameservers != nil {
+			for _, x := range *details.Nameservers {
+				nameservers = append(nameservers, x)
+			}
+		}
+		if details.ProviderType != nil {
+			switch providerType := strings.ToUpper(*details.ProviderType); {
+			case providerType == "CUSTOM":
+				mode = DNS_MODE_CUSTOM
+			case strings.Contains(providerType, "PREMIUM"):
+				mode = DNS_MODE_PREMIUM
+			}
+		}
+	}
+
+	return types.StringValue(mode), newNameserversValue(nameservers)
+}
+
+// maxPriceOf returns the `max_price` of the plan as an exact amount. The
+// float64 is converted to the shortest decimal that represents it, which is
+// the value written in the configuration.
+func maxPriceOf(plan *namecheapDomainState) sdk.Money {
+	currency := sdk.CurrencyUSD
+	if !plan.Currency.IsNull() && plan.Currency.ValueString() != "" {
+		currency = strings.ToUpper(plan.Currency.ValueString())
+	}
+	return sdk.Money{Amount: decimal.NewFromFloat(plan.MaxPrice.ValueFloat64()), Currency: currency}
+}
+
+// domainToASCII returns the punycode form of a domain name, which is the form
+// NameCheap expects and reports.
+func domainToASCII(domain string) (string, error) {
+	return idna.Lookup.ToASCII(strings.TrimSuffix(domain, "."))
+}
+
+// asciiDomainOf returns the punycode form of a domain attribute, or the value
+// as is if it isn't a valid domain name.
+func asciiDomainOf(domain types.String) string {
+	ascii, err := domainToASCII(domain.ValueString())
+	if err != nil {
+		return domain.ValueString()
+	}
+	return ascii
+}
+
+// unicodeDomainOf returns the Unicode form of a punycode domain name.
+func unicodeDomainOf(domain string) string {
+	unicode, err := idna.Lookup.ToUnicode(domain)
+	if err != nil {
+		return domain
+	}
+	return unicode
+}
+
+// domainRequiresReplace replaces the domain unless the Unicode and punycode
+// forms of the old and new name are the same.
+func domainRequiresReplace(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = asciiDomainOf(req.StateValue) != asciiDomainOf(req.PlanValue)
+}
+
 func log(ctx context.Context, format string, a ...any) {
 	tflog.Info(ctx, fmt.Sprintf(format, a...))
 }
@@ -1,5 +1,1232 @@
 package namecheap
 
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+const testAccDomainResourceName = "st-namecheap_domain.test"
+
+func testAccDomainConfig(srv *namecheaptest.Server, domain string, maxPrice float64, nameservers ...string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain             = %q
+  nameservers        = ["%s"]
+  max_price          = %g
+  purchase_years     = 1
+  min_days_remaining = 30
+}
+`, domain, strings.Join(nameservers, `", "`), maxPrice)
+}
+
+// testAccCheckDomainExpiresAfter verifies the fake account has the domain with
+// an expiry date later than the given time.
+func testAccCheckDomainExpiresAfter(srv *namecheaptest.Server, domain string, after time.Time) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		d, ok := srv.Domain(domain)
+		if !ok {
+			return fmt.Errorf("domain [%s] does not exist in the account", domain)
+		}
+		if !d.Expires.After(after) {
+			return fmt.Errorf("domain [%s] expires at %s, expected after %s", domain, d.Expires, after)
+		}
+		return nil
+	}
+}
+
+func TestAccDomainResource(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccDomainConfig(srv, "example.com", 10, "ns1.example.net", "ns2.example.net"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "domain", "example.com"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "nameservers.#", "2"),
+					resource.TestCheckTypeSetElemAttr(testAccDomainResourceName, "nameservers.*", "ns1.example.net"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "required_renew", "false"),
+					resource.TestCheckResourceAttrSet(testAccDomainResourceName, "domain_expiry_date"),
+					testAccCheckDomainExpiresAfter(srv, "example.com", time.Now().AddDate(0, 11, 0)),
+				),
+			},
+			// ImportState testing, max_price can't be read from NameCheap
+			{
+				ResourceName:                         testAccDomainResourceName,
+				ImportState:                          true,
+				ImportStateId:                        "example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain",
+				ImportStateVerifyIgnore:              []string{"max_price"},
+			},
+			{
+				ResourceName:                         testAccDomainResourceName,
+				ImportState:                          true,
+				ImportStateId:                        "example.com,1,30",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain",
+				ImportStateVerifyIgnore:              []string{"max_price"},
+			},
+			// max_price given in the identifier converges with the config.
+			{
+				ResourceName:                         testAccDomainResourceName,
+				ImportState:                          true,
+				ImportStateId:                        "example.com,1,30,10",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain",
+			},
+			{
+				ResourceName:  testAccDomainResourceName,
+				ImportState:   true,
+				ImportStateId: "example.com,1,30,free",
+				ExpectError:   regexp.MustCompile(`Expected a max_price greater than 0`),
+			},
+			{
+				ResourceName:  testAccDomainResourceName,
+				ImportState:   true,
+				ImportStateId: "example.com,1",
+				ExpectError:   regexp.MustCompile(`Unexpected import identifier`),
+			},
+			// Update nameservers without renewal
+			{
+				Config: testAccDomainConfig(srv, "example.com", 10, "ns3.example.net", "ns4.example.net"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccDomainResourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr(testAccDomainResourceName, "nameservers.*", "ns3.example.net"),
+					resource.TestCheckTypeSetElemAttr(testAccDomainResourceName, "nameservers.*", "ns4.example.net"),
+					func(_ *terraform.State) error {
+						d, _ := srv.Domain("example.com")
+						if strings.Join(d.Nameservers, ",") != "ns3.example.net,ns4.example.net" {
+							return fmt.Errorf("unexpected nameservers in the account: %v", d.Nameservers)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceRenew(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	config := testAccDomainConfig(srv, "renew.com", 10, "ns1.example.net", "ns2.example.net")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			// Expiry within min_days_remaining marks the domain for renewal
+			// in ModifyPlan, which Update then carries out.
+			{
+				PreConfig: func() {
+					srv.SetExpiry("renew.com", time.Now().AddDate(0, 0, 10))
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccDomainResourceName, plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue(testAccDomainResourceName, tfjsonpath.New("required_renew")),
+						plancheck.ExpectUnknownValue(testAccDomainResourceName, tfjsonpath.New("domain_expiry_date")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "required_renew", "false"),
+					testAccCheckDomainExpiresAfter(srv, "renew.com", time.Now().AddDate(0, 11, 0)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceReactivate(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	config := testAccDomainConfig(srv, "reactivate.com", 10, "ns1.example.net", "ns2.example.net")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					srv.SetExpiry("reactivate.com", time.Now().AddDate(0, 0, -5))
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDomainExpiresAfter(srv, "reactivate.com", time.Now()),
+					func(_ *terraform.State) error {
+						for _, command := range srv.Commands() {
+							if command == "namecheap.domains.reactivate" {
+								return nil
+							}
+						}
+						return fmt.Errorf("expected namecheap.domains.reactivate to be called")
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceOverprice(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDomainConfig(srv, "expensive.com", 5, "ns1.example.net", "ns2.example.net"),
+				ExpectError: regexp.MustCompile(`overprice`),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceMaxPriceBoundary(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	// The register price of 8.88 plus the ICANN fee of 0.18 equals max_price
+	// exactly, which must not be treated as overprice.
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDomainConfig(srv, "boundary.com", 9.06, "ns1.example.net", "ns2.example.net"),
+				Check:  resource.TestCheckResourceAttr(testAccDomainResourceName, "max_price", "9.06"),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceMaxPriceMultiYear(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	config := func(maxPrice float64) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain         = "multiyear.com"
+  nameservers    = ["ns1.example.net", "ns2.example.net"]
+  max_price      = %g
+  purchase_years = 2
+}
+`, maxPrice)
+	}
+
+	// The yearly price of 12.98 and ICANN fee of 0.18 are both charged for
+	// each of the 2 years.
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(13.16),
+				ExpectError: regexp.MustCompile(`overprice \[26.32 USD\]`),
+			},
+			{
+				Config: config(26.32),
+				Check: func(*terraform.State) error {
+					if got := srv.Balance(); math.Abs(got-(1000-26.32)) > 0.001 {
+						return fmt.Errorf("expected the balance to be charged 26.32, got %f", got)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceCurrencyMismatch(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "st-namecheap_domain" "test" {
+  domain         = "euro.com"
+  nameservers    = ["ns1.example.net", "ns2.example.net"]
+  max_price      = 100
+  currency       = "EUR"
+  purchase_years = 1
+}
+`,
+				ExpectError: regexp.MustCompile(`priced in USD, but max_price is in EUR`),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourcePremium(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.SetPremium("premium.com", namecheaptest.Premium{
+		RegistrationPrice: 120,
+		RenewalPrice:      95.5,
+		IcannFee:          0.18,
+	})
+
+	config := func(allowPremium bool, maxPrice float64) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain         = "premium.com"
+  nameservers    = ["ns1.example.net", "ns2.example.net"]
+  max_price      = %g
+  allow_premium  = %t
+  purchase_years = 1
+}
+`, maxPrice, allowPremium)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(false, 200),
+				ExpectError: regexp.MustCompile(`premium domain`),
+			},
+			{
+				Config:      config(true, 120),
+				ExpectError: regexp.MustCompile(`overprice`),
+			},
+			{
+				Config: config(true, 120.18),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "is_premium", "true"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "premium_renewal_price", "95.5"),
+					func(*terraform.State) error {
+						for _, r := range srv.Requests() {
+							if r.Get("Command") != "namecheap.domains.create" {
+								continue
+							}
+							if r.Get("IsPremiumDomain") != "true" || r.Get("PremiumPrice") != "120" {
+								return fmt.Errorf("unexpected premium parameters: %v", r)
+							}
+							return nil
+						}
+						return fmt.Errorf("namecheap.domains.create was not called")
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourcePremiumMultiYear(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.SetPremium("premium.com", namecheaptest.Premium{
+		RegistrationPrice: 120,
+		RenewalPrice:      95.5,
+		IcannFee:          0.18,
+	})
+
+	config := func(maxPrice float64) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain         = "premium.com"
+  nameservers    = ["ns1.example.net", "ns2.example.net"]
+  max_price      = %g
+  allow_premium  = true
+  purchase_years = 3
+}
+`, maxPrice)
+	}
+
+	// The first year is charged the registration price of 120, the other 2
+	// the renewal price of 95.5, and each of them the ICANN fee of 0.18.
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(120.18),
+				ExpectError: regexp.MustCompile(`overprice \[311.54 USD\]`),
+			},
+			{
+				Config: config(311.54),
+				Check: func(*terraform.State) error {
+					if got := srv.Balance(); math.Abs(got-(1000-311.54)) > 0.001 {
+						return fmt.Errorf("expected the balance to be charged 311.54, got %f", got)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceExtendedAttributes(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.SetPrice("register", "us", 1, 5)
+
+	config := func(extendedAttributes string) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain              = "example.us"
+  nameservers         = ["ns1.example.net", "ns2.example.net"]
+  max_price           = 10
+  purchase_years      = 1
+  extended_attributes = %s
+}
+`, extendedAttributes)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`{ RegistrantNexus = "C11" }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`requires the extended attributes \[RegistrantPurpose\]`),
+			},
+			{
+				Config:      config(`{ RegistrantNexus = "C11", RegistrantPurpose = "P1", Years = "10" }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`\[Years\] are set by the provider`),
+			},
+			{
+				Config: config(`{ RegistrantNexus = "C11", RegistrantPurpose = "P1" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "extended_attributes.RegistrantPurpose", "P1"),
+					func(*terraform.State) error {
+						for _, r := range srv.Requests() {
+							if r.Get("Command") != "namecheap.domains.create" {
+								continue
+							}
+							if r.Get("RegistrantNexus") != "C11" || r.Get("RegistrantPurpose") != "P1" || r.Has("Extended attributes") {
+								return fmt.Errorf("unexpected extended attributes: %v", r)
+							}
+							return nil
+						}
+						return fmt.Errorf("namecheap.domains.create was not called")
+					},
+				),
+			},
+			// The attributes are only required to register the domain.
+			{
+				Config:             config("null"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceIDN(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	var commands int
+	config := func(domain string) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain         = %q
+  idn_code       = "GER"
+  nameservers    = ["ns1.example.net", "ns2.example.net"]
+  max_price      = 10
+  purchase_years = 1
+}
+`, domain)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("bücher.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "domain", "bücher.com"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "domain_unicode", "bücher.com"),
+					func(*terraform.State) error {
+						if _, ok := srv.Domain("xn--bcher-kva.com"); !ok {
+							return fmt.Errorf("expected the punycode domain to be registered")
+						}
+						for _, r := range srv.Requests() {
+							if r.Get("Command") == "namecheap.domains.create" && r.Get("IdnCode") != "GER" {
+								return fmt.Errorf("expected IdnCode GER, got %q", r.Get("IdnCode"))
+							}
+						}
+						return nil
+					},
+				),
+			},
+			// Terraform requires the planned value of domain to be the configured
+			// one, so the other spelling is planned as an update of the state
+			// only, without any changing command sent to NameCheap.
+			{
+				PreConfig: func() { commands = len(srv.Commands()) },
+				Config:    config("xn--bcher-kva.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccDomainResourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "domain_unicode", "bücher.com"),
+					func(*terraform.State) error {
+						for _, command := range srv.Commands()[commands:] {
+							if !strings.Contains(command, ".get") && command != "namecheap.domains.check" {
+								return fmt.Errorf("expected no changing command for the other spelling, got %s", command)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: config("xn--bcher-kva.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestDomainValueSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+	unicode := domainValue{StringValue: types.StringValue("bücher.com")}
+
+	for other, expected := range map[string]bool{
+		"bücher.com":        true,
+		"xn--bcher-kva.com": true,
+		"XN--BCHER-KVA.COM": true,
+		"bucher.com":        false,
+	} {
+		equal, diags := unicode.StringSemanticEquals(ctx, domainValue{StringValue: types.StringValue(other)})
+		if diags.HasError() || equal != expected {
+			t.Errorf("expected %s to be equal %t, got %t (%v)", other, expected, equal, diags)
+		}
+	}
+}
+
+func TestAccDomainResourceDefaults(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "st-namecheap_domain" "test" {
+  domain      = "example.com"
+  nameservers = ["ns1.example.net", "ns2.example.net"]
+  max_price   = 10
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "purchase_years", "1"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "min_days_remaining", "30"),
+					func(*terraform.State) error {
+						for _, r := range srv.Requests() {
+							if r.Get("Command") == "namecheap.domains.create" && r.Get("Years") != "1" {
+								return fmt.Errorf("expected a 1 year registration, got %q", r.Get("Years"))
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceValidation(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	steps := []struct {
+		domain        string
+		nameservers   string
+		maxPrice      float64
+		purchaseYears int
+		err           string
+	}{
+		{"example.com", `["ns1.example.net", "ns2.example.net"]`, 10, 11, `between 1 and 10`},
+		{"example.com", `["ns1.example.net", "ns2.example.net"]`, 10, 0, `between 1 and 10`},
+		{"example.com", `["ns1.example.net", "ns2.example.net"]`, 0, 1, `greater than 0`},
+		{"exa_mple.com", `["ns1.example.net", "ns2.example.net"]`, 10, 1, `not a valid hostname`},
+		{"localhost", `["ns1.example.net", "ns2.example.net"]`, 10, 1, `not a valid hostname`},
+		{"example.com", `["ns1.example.net", "-ns2.example.net"]`, 10, 1, `not a valid hostname`},
+		{"example.com", `["ns1.example.net"]`, 10, 1, `must contain at least 2 elements`},
+	}
+
+	var testSteps []resource.TestStep
+	for _, step := range steps {
+		testSteps = append(testSteps, resource.TestStep{
+			Config: testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain         = %q
+  nameservers    = %s
+  max_price      = %g
+  purchase_years = %d
+}
+`, step.domain, step.nameservers, step.maxPrice, step.purchaseYears),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile(step.err),
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    testSteps,
+	})
+}
+
+func TestAccDomainResourceDNSMode(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	config := func(attributes string) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain         = "example.com"
+  max_price      = 10
+  purchase_years = 1
+  %s
+}
+`, attributes)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`dns_mode = "basic"
+  nameservers = ["ns1.example.net", "ns2.example.net"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`nameservers can only be set when dns_mode is custom`),
+			},
+			{
+				Config:      config(`dns_mode = "custom"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`nameservers must be set when dns_mode is custom`),
+			},
+			{
+				Config: config(``),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "dns_mode", "basic"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "nameservers.#", "2"),
+					resource.TestCheckTypeSetElemAttr(testAccDomainResourceName, "nameservers.*", "dns1.registrar-servers.com"),
+				),
+			},
+			{
+				Config: config(`nameservers = ["ns1.example.net", "ns2.example.net"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "dns_mode", "custom"),
+					resource.TestCheckTypeSetElemAttr(testAccDomainResourceName, "nameservers.*", "ns1.example.net"),
+				),
+			},
+			{
+				Config:      config(`dns_mode = "premium"`),
+				ExpectError: regexp.MustCompile(`no active PremiumDNS subscription`),
+			},
+			{
+				PreConfig: func() { srv.SetPremiumDNS("example.com", true) },
+				Config:    config(`dns_mode = "premium"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "dns_mode", "premium"),
+					resource.TestCheckTypeSetElemAttr(testAccDomainResourceName, "nameservers.*", "dns1.registrar-servers.com"),
+					func(*terraform.State) error {
+						if d, _ := srv.Domain("example.com"); len(d.Nameservers) != 0 {
+							return fmt.Errorf("expected default nameservers, got %v", d.Nameservers)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceBasicDNSWithPremiumSubscription(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.AddDomain(namecheaptest.Domain{
+		Name:       "example.com",
+		Created:    time.Now(),
+		Expires:    time.Now().AddDate(1, 0, 0),
+		PremiumDNS: true,
+	})
+
+	config := testAccProviderConfig(srv) + `
+resource "st-namecheap_domain" "test" {
+  domain         = "example.com"
+  dns_mode       = "basic"
+  max_price      = 10
+  adopt_existing = true
+}
+`
+
+	// The domain is on BasicDNS despite its PremiumDNS subscription.
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "dns_mode", "basic"),
+					func(*terraform.State) error {
+						for _, command := range srv.Commands() {
+							if command == "namecheap.domains.dns.setDefault" {
+								return fmt.Errorf("expected the domain to stay on BasicDNS, got %s", command)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccDomainResourcePremiumDNSCreate(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	config := func(dnsMode string) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain         = "premiumdns.com"
+  dns_mode       = %q
+  max_price      = 10
+  purchase_years = 1
+}
+`, dnsMode)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(DNS_MODE_PREMIUM),
+				ExpectError: regexp.MustCompile(`no active PremiumDNS subscription before it is registered`),
+			},
+			{
+				PreConfig: func() {
+					if _, ok := srv.Domain("premiumdns.com"); ok {
+						t.Error("expected the domain not to be bought")
+					}
+				},
+				Config: config(DNS_MODE_BASIC),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceCreateTimeout(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.Delay("namecheap.domains.create", 3*time.Second)
+	t.Setenv("NAMECHEAP_REQUEST_TIMEOUT", "1s")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDomainConfig(srv, "timeout.com", 10, "ns1.example.net", "ns2.example.net"),
+				ExpectError: regexp.MustCompile(`may have succeeded`),
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			creations := 0
+			for _, command := range srv.Commands() {
+				if command == "namecheap.domains.create" {
+					creations++
+				}
+			}
+			if creations != 1 {
+				return fmt.Errorf("expected the domain to be created once, got %d times", creations)
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccDomainResourceCreatePartialFailure(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	config := testAccProviderConfig(srv) + `
+resource "st-namecheap_domain" "test" {
+  domain         = "partial.com"
+  nameservers    = ["ns1.example.net", "ns2.example.net"]
+  max_price      = 10
+  purchase_years = 1
+  adopt_existing = true
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The domain is bought before listing it fails.
+			{
+				PreConfig:   func() { srv.FailNext("namecheap.domains.getList", "5050900", "Unhandled exception") },
+				Config:      config,
+				ExpectError: regexp.MustCompile(`Unhandled exception`),
+			},
+			// The domain stays in the state, tainted, and is adopted again
+			// instead of being bought twice.
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccDomainResourceName, plancheck.ResourceActionReplace),
+					},
+				},
+				Check: func(*terraform.State) error {
+					if got := srv.Balance(); math.Abs(got-(1000-9.06)) > 0.001 {
+						return fmt.Errorf("expected the domain to be charged once, got a balance of %f", got)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceNameserversSet(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	countSetCustom := func() int {
+		n := 0
+		for _, command := range srv.Commands() {
+			if command == "namecheap.domains.dns.setCustom" {
+				n++
+			}
+		}
+		return n
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDomainConfig(srv, "example.com", 10, "ns1.example.net", "ns2.example.net"),
+			},
+			{
+				// NameCheap reporting the nameservers in another order and
+				// case is not a change.
+				PreConfig:          func() { srv.SetNameservers("example.com", "NS2.EXAMPLE.NET", "NS1.EXAMPLE.NET") },
+				Config:             testAccDomainConfig(srv, "example.com", 10, "ns1.example.net", "ns2.example.net"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				PreConfig: func() {
+					if n := countSetCustom(); n != 0 {
+						t.Fatalf("expected no setCustom call yet, got %d", n)
+					}
+				},
+				Config: testAccDomainConfig(srv, "example.com", 20, "ns1.example.net", "ns2.example.net"),
+				Check: func(*terraform.State) error {
+					if n := countSetCustom(); n != 0 {
+						return fmt.Errorf("expected updating max_price not to set nameservers, got %d calls", n)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccDomainConfig(srv, "example.com", 20, "ns1.example.net", "ns3.example.net"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr(testAccDomainResourceName, "nameservers.*", "ns3.example.net"),
+					func(*terraform.State) error {
+						if n := countSetCustom(); n != 1 {
+							return fmt.Errorf("expected one setCustom call, got %d", n)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceAutoRenew(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	config := func(autoRenew string) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain      = "example.com"
+  nameservers = ["ns1.example.net", "ns2.example.net"]
+  max_price   = 10
+  auto_renew  = %s
+}
+`, autoRenew)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("true"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`New registrations start with auto-renew off`),
+			},
+			{
+				Config: config("null"),
+				Check:  resource.TestCheckResourceAttr(testAccDomainResourceName, "auto_renew", "false"),
+			},
+			{
+				Config:      config("true"),
+				ExpectError: regexp.MustCompile(`Auto-renew cannot be changed`),
+			},
+			{
+				PreConfig: func() { srv.SetAutoRenew("example.com", true) },
+				Config:    config("true"),
+				Check:     resource.TestCheckResourceAttr(testAccDomainResourceName, "auto_renew", "true"),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceDeletionPolicy(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	config := func(deletionPolicy string) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain          = "example.com"
+  nameservers     = ["ns1.example.net", "ns2.example.net"]
+  max_price       = 10
+  deletion_policy = %q
+}
+`, deletionPolicy)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			d, ok := srv.Domain("example.com")
+			if !ok {
+				return fmt.Errorf("expected example.com to remain registered")
+			}
+			if len(d.Nameservers) != 0 || d.WhoisGuard || d.AutoRenew {
+				return fmt.Errorf("expected example.com to be released, got %+v", d)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("prevent"),
+				Check:  resource.TestCheckResourceAttr(testAccDomainResourceName, "deletion_policy", "prevent"),
+			},
+			{
+				Config:      config("prevent"),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Domain deletion prevented`),
+			},
+			{
+				PreConfig: func() {
+					srv.SetWhoisGuard("example.com", true)
+					srv.SetAutoRenew("example.com", true)
+				},
+				Config: config("release"),
+			},
+			// Release fails, leaving the domain as is, until auto-renew is
+			// turned off in the dashboard.
+			{
+				Config:      config("release"),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Auto-renew is still on`),
+			},
+			{
+				PreConfig: func() {
+					if d, _ := srv.Domain("example.com"); len(d.Nameservers) == 0 || !d.WhoisGuard {
+						t.Errorf("expected the failed release not to change example.com, got %+v", d)
+					}
+					srv.SetAutoRenew("example.com", false)
+				},
+				Config: config("release"),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceAdoptExisting(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.AddDomain(namecheaptest.Domain{
+		Name:        "owned.com",
+		Expires:     time.Now().AddDate(1, 0, 0),
+		Nameservers: []string{"ns1.old.net", "ns2.old.net"},
+	})
+
+	config := func(adoptExisting bool) string {
+		return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "st-namecheap_domain" "test" {
+  domain         = "owned.com"
+  nameservers    = ["ns1.example.net", "ns2.example.net"]
+  max_price      = 10
+  adopt_existing = %t
+}
+`, adoptExisting)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(false),
+				ExpectError: regexp.MustCompile(`set adopt_existing to manage it`),
+			},
+			{
+				Config: config(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "dns_mode", "custom"),
+					resource.TestCheckTypeSetElemAttr(testAccDomainResourceName, "nameservers.*", "ns1.example.net"),
+					func(*terraform.State) error {
+						for _, command := range srv.Commands() {
+							if command == "namecheap.domains.create" {
+								return fmt.Errorf("expected the owned domain not to be purchased")
+							}
+						}
+						if srv.Balance() != 1000 {
+							return fmt.Errorf("expected the balance not to be charged, got %f", srv.Balance())
+						}
+						if d, _ := srv.Domain("owned.com"); strings.Join(d.Nameservers, ",") != "ns1.example.net,ns2.example.net" {
+							return fmt.Errorf("expected nameservers to be reconciled, got %v", d.Nameservers)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceAdoptExistingAutoRenew(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	expires := time.Now().AddDate(1, 0, 0)
+	srv.AddDomain(namecheaptest.Domain{
+		Name:      "owned.com",
+		Expires:   expires,
+		AutoRenew: true,
+	})
+	// Also matches the search for owned.com, and is listed first.
+	srv.AddDomain(namecheaptest.Domain{
+		Name:    "disowned.com",
+		Expires: time.Now().AddDate(5, 0, 0),
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+resource "st-namecheap_domain" "test" {
+  domain         = "owned.com"
+  max_price      = 10
+  adopt_existing = true
+  auto_renew     = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "auto_renew", "true"),
+					resource.TestCheckResourceAttrWith(testAccDomainResourceName, "domain_expiry_date", func(value string) error {
+						expiry, err := time.Parse(time.RFC3339, value)
+						if err != nil {
+							return err
+						}
+						if d := expiry.Sub(expires); d < -48*time.Hour || d > 48*time.Hour {
+							return fmt.Errorf("expected the expiry date of owned.com around %s, got %s", expires, value)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceImportBlock(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.AddDomain(namecheaptest.Domain{
+		Name:        "owned.com",
+		Expires:     time.Now().AddDate(1, 0, 0),
+		Nameservers: []string{"ns1.example.net", "ns2.example.net"},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(srv) + `
+import {
+  to = st-namecheap_domain.test
+  id = "owned.com,2,60,10"
+}
+
+resource "st-namecheap_domain" "test" {
+  domain             = "owned.com"
+  nameservers        = ["ns1.example.net", "ns2.example.net"]
+  max_price          = 10
+  purchase_years     = 2
+  min_days_remaining = 60
+}
+`,
+				// Nothing but the import is planned, max_price included.
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(testAccDomainResourceName, plancheck.ResourceActionNoop),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "purchase_years", "2"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "min_days_remaining", "60"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "dns_mode", "custom"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "required_renew", "false"),
+					func(*terraform.State) error {
+						for _, command := range srv.Commands() {
+							switch command {
+							case "namecheap.domains.create", "namecheap.domains.renew", "namecheap.domains.dns.setCustom":
+								return fmt.Errorf("expected the import not to call %s", command)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceAccount(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.AddDomain(namecheaptest.Domain{
+		Name:        "brand.com",
+		Expires:     time.Now().AddDate(1, 0, 0),
+		Nameservers: []string{"ns1.example.net", "ns2.example.net"},
+	})
+
+	// Only the brand account has the credentials the fake server accepts, so
+	// any call made with the top-level credentials fails.
+	config := func(account string) string {
+		return fmt.Sprintf(`
+provider "st-namecheap" {
+  user_name   = "legacy"
+  api_user    = "legacy"
+  api_key     = "wrongkey"
+  client_ip   = %q
+  use_sandbox = false
+  endpoint    = %q
+
+  accounts = {
+    brand = {
+      user_name = %q
+      api_user  = %q
+      api_key   = %q
+    }
+  }
+}
+
+resource "st-namecheap_domain" "test" {
+  domain         = "brand.com"
+  account        = %q
+  nameservers    = ["ns1.example.net", "ns2.example.net"]
+  max_price      = 10
+  adopt_existing = true
+}
+`, namecheaptest.ClientIp, srv.Endpoint(), namecheaptest.UserName, namecheaptest.ApiUser, namecheaptest.ApiKey, account)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("reseller"),
+				ExpectError: regexp.MustCompile(`Unknown account`),
+			},
+			{
+				Config: config("brand"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "account", "brand"),
+					resource.TestCheckResourceAttr(testAccDomainResourceName, "dns_mode", "custom"),
+				),
+			},
+			{
+				ResourceName:                         testAccDomainResourceName,
+				ImportState:                          true,
+				ImportStateId:                        "brand/brand.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain",
+				ImportStateVerifyIgnore:              []string{"max_price", "adopt_existing"},
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceUnavailable(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	srv.SetUnavailable("taken.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDomainConfig(srv, "taken.com", 10, "ns1.example.net", "ns2.example.net"),
+				ExpectError: regexp.MustCompile(`not available to register`),
+			},
+		},
+	})
+}
+
 // equalDomainRecord compares only Name, Type, Address, TTL, MXPref fields only
 // func equalDomainRecord(sRec *namecheap.DomainsDNSHostRecordDetailed, dRec *namecheap.DomainsDNSHostRecordDetailed) bool {
 // 	return *sRec.Name == *dRec.Name &&
@@ -8,3 +1235,76 @@ package namecheap
 // 		*sRec.TTL == *dRec.TTL &&
 // 		*sRec.MXPref == *dRec.MXPref
 // }
+
+func TestDomainResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &namecheapDomainResource{}
+	upgrader := r.UpgradeState(ctx)[0]
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	nameservers := []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "NS1.example.net"),
+		tftypes.NewValue(tftypes.String, "ns2.example.net"),
+		tftypes.NewValue(tftypes.String, "NS1.example.net"),
+	}
+	prior := tftypes.NewValue(upgrader.PriorSchema.Type().TerraformType(ctx), map[string]tftypes.Value{
+		"domain":             tftypes.NewValue(tftypes.String, "example.com"),
+		"nameservers":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nameservers),
+		"max_price":          tftypes.NewValue(tftypes.Number, 10.5),
+		"min_days_remaining": tftypes.NewValue(tftypes.Number, nil),
+		"purchase_years":     tftypes.NewValue(tftypes.Number, 2),
+		"domain_expiry_date": tftypes.NewValue(tftypes.String, "2024-12-30T14:59:59Z"),
+		"required_renew":     tftypes.NewValue(tftypes.Bool, false),
+	})
+
+	req := fwresource.UpgradeStateRequest{State: &tfsdk.State{Raw: prior, Schema: *upgrader.PriorSchema}}
+	resp := fwresource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var state namecheapDomainState
+	if diags := resp.State.Get(ctx, &state); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got := state.Nameservers.hostnames(); len(got) != 2 || !sameNameservers(got, []string{"ns1.example.net", "ns2.example.net"}) {
+		t.Errorf("nameservers = %v, want the two distinct hostnames", got)
+	}
+	if got := state.DomainExpiryDate.ValueString(); got != "2024-12-30T14:59:59Z" {
+		t.Errorf("domain_expiry_date = %q", got)
+	}
+	if state.MaxPrice.ValueFloat64() != 10.5 || state.Years.ValueInt64() != 2 || state.MinDaysRemaining.ValueInt64() != 30 {
+		t.Errorf("max_price, purchase_years, min_days_remaining = %v, %v, %v", state.MaxPrice, state.Years, state.MinDaysRemaining)
+	}
+	if state.DNSMode.ValueString() != DNS_MODE_CUSTOM || state.DeletionPolicy.ValueString() != DELETION_POLICY_ABANDON {
+		t.Errorf("dns_mode, deletion_policy = %v, %v", state.DNSMode, state.DeletionPolicy)
+	}
+	if state.DomainUnicode.ValueString() != "example.com" || state.RequiredRenew.ValueBool() {
+		t.Errorf("domain_unicode, required_renew = %v, %v", state.DomainUnicode, state.RequiredRenew)
+	}
+}
+
+func TestIsExpired(t *testing.T) {
+	now := time.Date(2024, 3, 24, 0, 0, 0, 0, time.UTC)
+	yes, no := true, false
+	past := &namecheap.DateTime{Time: now.AddDate(0, 0, -1)}
+	future := &namecheap.DateTime{Time: now.AddDate(1, 0, 0)}
+
+	for name, tc := range map[string]struct {
+		entry    namecheap.Domain
+		expected bool
+	}{
+		"expired":              {namecheap.Domain{IsExpired: &yes, Expires: future}, true},
+		"not expired":          {namecheap.Domain{IsExpired: &no, Expires: past}, false},
+		"no IsExpired, past":   {namecheap.Domain{Expires: past}, true},
+		"no IsExpired, future": {namecheap.Domain{Expires: future}, false},
+		"neither":              {namecheap.Domain{}, false},
+	} {
+		if got := isExpired(&tc.entry, now); got != tc.expected {
+			t.Errorf("%s: expected %t, got %t", name, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package namecheap
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// namecheapDomainStateV0 is the state of schema version 0, where nameservers
+// were an ordered list and the defaults of the optional attributes were not
+// stored.
+type namecheapDomainStateV0 struct {
+	Domain           types.String  `tfsdk:"domain"`
+	Nameservers      types.List    `tfsdk:"nameservers"`
+	MaxPrice         types.Float64 `tfsdk:"max_price"`
+	MinDaysRemaining types.Int64   `tfsdk:"min_days_remaining"`
+	Years            types.Int64   `tfsdk:"purchase_years"`
+	DomainExpiryDate types.String  `tfsdk:"domain_expiry_date"`
+	RequiredRenew    types.Bool    `tfsdk:"required_renew"`
+}
+
+// UpgradeState migrates states written by earlier schema versions, so that
+// existing domains are kept instead of being replaced.
+func (r *namecheapDomainResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"domain": schema.StringAttribute{
+						Required: true,
+					},
+					"nameservers": schema.ListAttribute{
+						ElementType: types.StringType,
+						Required:    true,
+					},
+					"max_price": schema.Float64Attribute{
+						Required: true,
+					},
+					"min_days_remaining": schema.Int64Attribute{
+						Optional: true,
+					},
+					"purchase_years": schema.Int64Attribute{
+						Optional: true,
+					},
+					"domain_expiry_date": schema.StringAttribute{
+						Computed: true,
+					},
+					"required_renew": schema.BoolAttribute{
+						Computed: true,
+					},
+				},
+			},
+			StateUpgrader: upgradeDomainStateV0,
+		},
+	}
+}
+
+// upgradeDomainStateV0 converts the nameservers to a set and the expiry date
+// to RFC 3339, and fills in the attributes added since version 0 with the
+// values NameCheap domains had back then. Attributes that are only known to
+// NameCheap are left null and refreshed by the next read.
+func upgradeDomainStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior namecheapDomainStateV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameservers []string
+	resp.Diagnostics.Append(prior.Nameservers.ElementsAs(ctx, &nameservers, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := namecheapDomainState{
+		Domain:              domainValue{StringValue: prior.Domain},
+		DomainUnicode:       types.StringValue(unicodeDomainOf(asciiDomainOf(prior.Domain))),
+		IdnCode:             types.StringNull(),
+		Nameservers:         newNameserversValue(uniqueStrings(nameservers)),
+		DNSMode:             types.StringValue(DNS_MODE_CUSTOM),
+		AutoRenew:           types.BoolNull(),
+		DeletionPolicy:      types.StringValue(DELETION_POLICY_ABANDON),
+		MaxPrice:            prior.MaxPrice,
+		Currency:            types.StringNull(),
+		AllowPremium:        types.BoolNull(),
+		AdoptExisting:       types.BoolNull(),
+		ExtendedAttributes:  types.MapNull(types.StringType),
+		MinDaysRemaining:    prior.MinDaysRemaining,
+		Years:               prior.Years,
+		DomainExpiryDate:    upgradeExpiryDate(prior.DomainExpiryDate),
+		RequiredRenew:       prior.RequiredRenew,
+		IsPremium:           types.BoolNull(),
+		PremiumRenewalPrice: types.Float64Null(),
+	}
+	if state.MinDaysRemaining.IsNull() {
+		state.MinDaysRemaining = types.Int64Value(30)
+	}
+	if state.Years.IsNull() {
+		state.Years = types.Int64Value(1)
+	}
+
+	log(ctx, "[upgradeDomainStateV0] upgraded state of domain [%s] to version 1", prior.Domain.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// upgradeExpiryDate returns the expiry date in RFC 3339, or null if it can't
+// be parsed, in which case the next read stores it again.
+func upgradeExpiryDate(expiry types.String) types.String {
+	if expiry.IsNull() || expiry.IsUnknown() {
+		return types.StringNull()
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05Z", "2006-01-02"} {
+		if t, err := time.Parse(layout, expiry.ValueString()); err == nil {
+			return types.StringValue(formatExpiryDate(t))
+		}
+	}
+	return types.StringNull()
+}
+
+// uniqueStrings returns values without duplicates, as sets can't hold them.
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, x := range values {
+		if !seen[x] {
+			seen[x] = true
+			unique = append(unique, x)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,583 @@
+package namecheaptest
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+)
+
+const dateLayout = "01/02/2006"
+
+// icannFee is the additional cost reported by users.getPricing and charged
+// yearly on top of every regular registration, renewal and reactivation.
+const icannFee = 0.18
+
+type handlerFunc func(s *Server, params url.Values, resp *response)
+
+var handlers = map[string]handlerFunc{
+	"namecheap.domains.check":          handleDomainsCheck,
+	"namecheap.domains.create":         handleDomainsCreate,
+	"namecheap.domains.getInfo":        handleDomainsGetInfo,
+	"namecheap.domains.getList":        handleDomainsGetList,
+	"namecheap.domains.renew":          handleDomainsRenew,
+	"namecheap.domains.reactivate":     handleDomainsReactivate,
+	"namecheap.domains.dns.setCustom":  handleDNSSetCustom,
+	"namecheap.domains.dns.setDefault": handleDNSSetDefault,
+	"namecheap.domains.dns.getHosts":   handleDNSGetHosts,
+	"namecheap.domains.dns.setHosts":   handleDNSSetHosts,
+	"namecheap.users.getPricing":       handleUsersGetPricing,
+	"namecheap.users.address.getInfo":  handleUsersAddressGetInfo,
+	"namecheap.users.address.getList":  handleUsersAddressGetList,
+	"namecheap.whoisguard.getList":     handleWhoisguardGetList,
+	"namecheap.whoisguard.disable":     handleWhoisguardDisable,
+}
+
+// domainName returns the domain a request refers to, either from DomainName
+// or from the SLD and TLD parameters.
+func domainName(params url.Values) string {
+	if name := params.Get("DomainName"); name != "" {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(params.Get("SLD") + "." + params.Get("TLD"))
+}
+
+func tldOf(domain string) string {
+	parsed, err := namecheap.ParseDomain(domain)
+	if err != nil {
+		return ""
+	}
+	return parsed.TLD
+}
+
+// ownedDomain looks up a domain of the account and reports the same error as
+// NameCheap when it is not found.
+func ownedDomain(s *Server, params url.Values, resp *response) *Domain {
+	d, ok := s.domains[domainName(params)]
+	if !ok {
+		resp.fail("2030166", "Domain is invalid")
+		return nil
+	}
+	return d
+}
+
+func years(params url.Values, name string, resp *response) (int, bool) {
+	value := params.Get(name)
+	if value == "" {
+		value = "1"
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 10 {
+		resp.fail("2011171", fmt.Sprintf("Parameter %s is invalid", name))
+		return 0, false
+	}
+	return n, true
+}
+
+// charge deducts amount from the account balance, failing the response when
+// the balance is insufficient.
+func charge(s *Server, amount float64, resp *response) bool {
+	if amount > s.balance {
+		resp.fail("2528166", "Order creation failed: insufficient funds in the account")
+		return false
+	}
+	s.balance -= amount
+	return true
+}
+
+func handleDomainsCheck(s *Server, params url.Values, resp *response) {
+	for _, name := range strings.Split(params.Get("DomainList"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		_, owned := s.domains[name]
+		premium, isPremium := s.premium[name]
+		resp.element("DomainCheckResult",
+			"Domain", name,
+			"Available", !owned && !s.unavailable[name],
+			"ErrorNo", 0,
+			"Description", "",
+			"IsPremiumName", isPremium,
+			"PremiumRegistrationPrice", premium.RegistrationPrice,
+			"PremiumRenewalPrice", premium.RenewalPrice,
+			"PremiumRestorePrice", 0.0,
+			"PremiumTransferPrice", 0.0,
+			"IcannFee", premium.IcannFee,
+			"EapFee", premium.EapFee,
+		)
+	}
+}
+
+func handleDomainsCreate(s *Server, params url.Values, resp *response) {
+	name := domainName(params)
+	n, ok := years(params, "Years", resp)
+	if !ok {
+		return
+	}
+	if _, owned := s.domains[name]; owned || s.unavailable[name] {
+		resp.fail("3019166", fmt.Sprintf("Domain not available (%s)", name))
+		return
+	}
+	if params.Get("RegistrantEmailAddress") == "" {
+		resp.fail("2010324", "RegistrantEmailAddress is Missing")
+		return
+	}
+
+	premium, isPremium := s.premium[name]
+	var amount float64
+	if isPremium {
+		if params.Get("IsPremiumDomain") != "true" {
+			resp.fail("2515623", "Domain is premium and IsPremiumDomain parameter is missing")
+			return
+		}
+		if p, err := strconv.ParseFloat(params.Get("PremiumPrice"), 64); err != nil || p != premium.RegistrationPrice {
+			resp.fail("2515624", "PremiumPrice does not match the price of the premium domain")
+			return
+		}
+		amount = premium.RegistrationPrice + premium.EapFee + premium.IcannFee +
+			(premium.RenewalPrice+premium.IcannFee)*float64(n-1)
+		amount = math.Round(amount*100) / 100
+	} else {
+		cost, ok := s.cost("register", tldOf(name), n)
+		if !ok {
+			resp.fail("2030280", fmt.Sprintf("TLD is not supported for %d years", n))
+			return
+		}
+		amount = cost
+	}
+	if !charge(s, amount, resp) {
+		return
+	}
+
+	now := s.Now()
+	d := &Domain{
+		Name:      name,
+		Created:   now,
+		Expires:   now.AddDate(n, 0, 0),
+		IsPremium: isPremium,
+	}
+	if ns := params.Get("Nameservers"); ns != "" {
+		for _, x := range strings.Split(ns, ",") {
+			if x = strings.TrimSpace(x); x != "" {
+				d.Nameservers = append(d.Nameservers, x)
+			}
+		}
+	}
+	s.domains[name] = d
+
+	resp.element("DomainCreateResult",
+		"Domain", name,
+		"Registered", true,
+		"ChargedAmount", amount,
+		"DomainID", s.id(),
+		"OrderID", s.id(),
+		"TransactionID", s.id(),
+		"WhoisguardEnable", false,
+		"NonRealTimeDomain", false,
+	)
+}
+
+func handleDomainsGetInfo(s *Server, params url.Values, resp *response) {
+	d := ownedDomain(s, params, resp)
+	if d == nil {
+		return
+	}
+
+	status := "Ok"
+	if s.isExpired(d) {
+		status = "Expired"
+	}
+	providerType, usingOurDNS := "CUSTOM", false
+	if len(d.Nameservers) == 0 {
+		providerType, usingOurDNS = "FREE", true
+		if d.OnPremiumDNS {
+			providerType = "PREMIUM"
+		}
+	}
+
+	resp.open("DomainGetInfoResult",
+		"Status", status,
+		"ID", 1,
+		"DomainName", d.Name,
+		"OwnerName", UserName,
+		"IsOwner", true,
+		"IsPremium", d.IsPremium,
+	)
+	resp.open("DomainDetails")
+	resp.text("CreatedDate", d.Created.Format(dateLayout))
+	resp.text("ExpiredDate", d.Expires.Format(dateLayout))
+	resp.text("NumYears", 0)
+	resp.close("DomainDetails")
+	resp.open("PremiumDnsSubscription")
+	resp.text("IsActive", d.PremiumDNS)
+	resp.close("PremiumDnsSubscription")
+	resp.open("DnsDetails",
+		"ProviderType", providerType,
+		"IsUsingOurDNS", usingOurDNS,
+		"HostCount", len(d.Hosts),
+		"EmailType", "FWD",
+		"DynamicDNSStatus", false,
+		"IsFailover", false,
+	)
+	nameservers := d.Nameservers
+	if usingOurDNS {
+		nameservers = []string{"dns1.registrar-servers.com", "dns2.registrar-servers.com"}
+	}
+	for _, x := range nameservers {
+		resp.text("Nameserver", x)
+	}
+	resp.close("DnsDetails")
+	resp.close("DomainGetInfoResult")
+}
+
+func handleDomainsGetList(s *Server, params url.Values, resp *response) {
+	search := strings.ToLower(params.Get("SearchTerm"))
+	listType := strings.ToUpper(params.Get("ListType"))
+
+	var matched []*Domain
+	for _, d := range s.sortedDomains() {
+		if search != "" && !strings.Contains(d.Name, search) {
+			continue
+		}
+		if listType == "EXPIRED" && !s.isExpired(d) {
+			continue
+		}
+		matched = append(matched, d)
+	}
+
+	resp.open("DomainGetListResult")
+	for _, d := range matched {
+		resp.element("Domain",
+			"ID", 1,
+			"Name", d.Name,
+			"User", UserName,
+			"Created", d.Created.Format(dateLayout),
+			"Expires", d.Expires.Format(dateLayout),
+			"IsExpired", s.isExpired(d),
+			"IsLocked", d.IsLocked,
+			"AutoRenew", d.AutoRenew,
+			"WhoisGuard", whoisGuardStatus(d),
+			"IsPremium", d.IsPremium,
+			"IsOurDNS", len(d.Nameservers) == 0,
+		)
+	}
+	resp.close("DomainGetListResult")
+	resp.open("Paging")
+	resp.text("TotalItems", len(matched))
+	resp.text("CurrentPage", 1)
+	resp.text("PageSize", 20)
+	resp.close("Paging")
+}
+
+func handleDomainsRenew(s *Server, params url.Values, resp *response) {
+	d := ownedDomain(s, params, resp)
+	if d == nil {
+		return
+	}
+	n, ok := years(params, "Years", resp)
+	if !ok {
+		return
+	}
+	if s.isExpired(d) {
+		resp.fail("2020166", "Domain has expired, please reactivate it")
+		return
+	}
+	cost, ok := s.cost("renew", tldOf(d.Name), n)
+	if !ok {
+		resp.fail("2030280", fmt.Sprintf("TLD is not supported for %d years", n))
+		return
+	}
+	if !charge(s, cost, resp) {
+		return
+	}
+	d.Expires = d.Expires.AddDate(n, 0, 0)
+
+	resp.open("DomainRenewResult",
+		"DomainName", d.Name,
+		"DomainID", 1,
+		"Renew", true,
+		"OrderID", s.id(),
+		"TransactionID", s.id(),
+		"ChargedAmount", cost,
+	)
+	resp.open("DomainDetails")
+	resp.text("ExpiredDate", d.Expires.Format(dateLayout))
+	resp.text("NumYears", n)
+	resp.close("DomainDetails")
+	resp.close("DomainRenewResult")
+}
+
+func handleDomainsReactivate(s *Server, params url.Values, resp *response) {
+	d := ownedDomain(s, params, resp)
+	if d == nil {
+		return
+	}
+	n, ok := years(params, "YearsToAdd", resp)
+	if !ok {
+		return
+	}
+	if !s.isExpired(d) {
+		resp.fail("2019166", "Domain is not expired and cannot be reactivated")
+		return
+	}
+	cost, ok := s.cost("reactivate", tldOf(d.Name), n)
+	if !ok {
+		resp.fail("2030280", fmt.Sprintf("TLD is not supported for %d years", n))
+		return
+	}
+	if !charge(s, cost, resp) {
+		return
+	}
+	d.Expires = d.Expires.AddDate(n, 0, 0)
+	if now := s.Now(); d.Expires.Before(now) {
+		d.Expires = now.AddDate(n, 0, 0)
+	}
+
+	resp.element("DomainReactivateResult",
+		"Domain", d.Name,
+		"IsSuccess", true,
+		"ChargedAmount", cost,
+		"OrderID", s.id(),
+		"TransactionID", s.id(),
+	)
+}
+
+func handleDNSSetCustom(s *Server, params url.Values, resp *response) {
+	d := ownedDomain(s, params, resp)
+	if d == nil {
+		return
+	}
+
+	var nameservers []string
+	for _, x := range strings.Split(params.Get("Nameservers"), ",") {
+		if x = strings.TrimSpace(x); x != "" {
+			nameservers = append(nameservers, x)
+		}
+	}
+	if len(nameservers) < 2 {
+		resp.fail("2011147", "Nameservers must contain at least two entries")
+		return
+	}
+	d.Nameservers = nameservers
+
+	resp.element("DomainDNSSetCustomResult", "Domain", d.Name, "Updated", true)
+}
+
+func handleDNSSetDefault(s *Server, params url.Values, resp *response) {
+	d := ownedDomain(s, params, resp)
+	if d == nil {
+		return
+	}
+	d.Nameservers = nil
+	d.OnPremiumDNS = d.PremiumDNS
+
+	resp.element("DomainDNSSetDefaultResult", "Domain", d.Name, "Updated", true)
+}
+
+func handleDNSGetHosts(s *Server, params url.Values, resp *response) {
+	d := ownedDomain(s, params, resp)
+	if d == nil {
+		return
+	}
+
+	resp.open("DomainDNSGetHostsResult",
+		"Domain", d.Name,
+		"EmailType", "FWD",
+		"IsUsingOurDNS", len(d.Nameservers) == 0,
+	)
+	for i, h := range d.Hosts {
+		resp.element("host",
+			"HostId", i+1,
+			"Name", h.Name,
+			"Type", h.Type,
+			"Address", h.Address,
+			"MXPref", h.MXPref,
+			"TTL", h.TTL,
+			"AssociatedAppTitle", "",
+			"FriendlyName", "",
+			"IsActive", true,
+			"IsDDNSEnabled", false,
+		)
+	}
+	resp.close("DomainDNSGetHostsResult")
+}
+
+func handleDNSSetHosts(s *Server, params url.Values, resp *response) {
+	d := ownedDomain(s, params, resp)
+	if d == nil {
+		return
+	}
+
+	var hosts []Host
+	for i := 1; params.Get(fmt.Sprintf("HostName%d", i)) != ""; i++ {
+		h := Host{
+			Name:    params.Get(fmt.Sprintf("HostName%d", i)),
+			Type:    params.Get(fmt.Sprintf("RecordType%d", i)),
+			Address: params.Get(fmt.Sprintf("Address%d", i)),
+			MXPref:  10,
+			TTL:     1800,
+		}
+		if v, err := strconv.Atoi(params.Get(fmt.Sprintf("MXPref%d", i))); err == nil {
+			h.MXPref = v
+		}
+		if v, err := strconv.Atoi(params.Get(fmt.Sprintf("TTL%d", i))); err == nil {
+			h.TTL = v
+		}
+		hosts = append(hosts, h)
+	}
+	d.Hosts = hosts
+
+	resp.element("DomainDNSSetHostsResult", "Domain", d.Name, "IsSuccess", true)
+}
+
+func handleUsersGetPricing(s *Server, params url.Values, resp *response) {
+	if !strings.EqualFold(params.Get("ProductType"), "DOMAIN") {
+		resp.fail("2011170", "Parameter ProductType is invalid")
+		return
+	}
+	action := strings.ToLower(params.Get("ActionName"))
+	tld := strings.ToLower(params.Get("ProductName"))
+
+	resp.open("UserGetPricingResult")
+	resp.open("ProductType", "Name", "domains")
+	for _, category := range []string{"register", "renew", "reactivate"} {
+		if action != "" && action != category {
+			continue
+		}
+		resp.open("ProductCategory", "Name", category)
+		var products []string
+		for productName := range s.pricing[category] {
+			if tld == "" || tld == productName {
+				products = append(products, productName)
+			}
+		}
+		sort.Strings(products)
+		for _, productName := range products {
+			durations := s.pricing[category][productName]
+			resp.open("Product", "Name", productName)
+			for n := 1; n <= 10; n++ {
+				price, ok := durations[n]
+				if !ok {
+					continue
+				}
+				resp.element("Price",
+					"Duration", n,
+					"DurationType", "YEAR",
+					"Price", price,
+					"PricingType", "MULTIPLE",
+					"AdditionalCost", icannFee,
+					"RegularPrice", price,
+					"RegularPriceType", "MULTIPLE",
+					"RegularAdditionalCost", icannFee,
+					"RegularAdditionalCostType", "MULTIPLE",
+					"YourPrice", price,
+					"YourPriceType", "MULTIPLE",
+					"YourAdditonalCost", icannFee,
+					"YourAdditonalCostType", "MULTIPLE",
+					"PromotionPrice", 0.0,
+					"Currency", "USD",
+				)
+			}
+			resp.close("Product")
+		}
+		resp.close("ProductCategory")
+	}
+	resp.close("ProductType")
+	resp.close("UserGetPricingResult")
+}
+
+func handleUsersAddressGetInfo(s *Server, params url.Values, resp *response) {
+	id := params.Get("AddressId")
+	if id != "0" && id != s.address.AddressId {
+		resp.fail("2011288", "Invalid AddressId")
+		return
+	}
+
+	a := s.address
+	resp.open("GetAddressInfoResult")
+	resp.text("AddressId", a.AddressId)
+	resp.text("UserName", UserName)
+	resp.text("AddressName", a.AddressName)
+	resp.text("Default_YN", true)
+	resp.text("FirstName", a.FirstName)
+	resp.text("LastName", a.LastName)
+	resp.text("JobTitle", "")
+	resp.text("Organization", "")
+	resp.text("Address1", a.Address1)
+	resp.text("Address2", a.Address2)
+	resp.text("City", a.City)
+	resp.text("StateProvince", a.StateProvince)
+	resp.text("StateProvinceChoice", "S")
+	resp.text("Zip", a.PostalCode)
+	resp.text("Country", a.Country)
+	resp.text("Phone", a.Phone)
+	resp.text("PhoneExt", "")
+	resp.text("EmailAddress", a.EmailAddress)
+	resp.close("GetAddressInfoResult")
+}
+
+func handleUsersAddressGetList(s *Server, _ url.Values, resp *response) {
+	resp.open("AddressGetListResult")
+	resp.element("List", "AddressId", s.address.AddressId, "AddressName", s.address.AddressName)
+	resp.close("AddressGetListResult")
+}
+
+func whoisGuardStatus(d *Domain) string {
+	if d.WhoisGuard {
+		return "ENABLED"
+	}
+	return "NOTPRESENT"
+}
+
+// whoisguardID derives the ID of the privacy protection of a domain from its
+// position in the sorted domain list.
+func whoisguardID(s *Server, d *Domain) int {
+	for i, x := range s.sortedDomains() {
+		if x == d {
+			return 50000 + i
+		}
+	}
+	return 0
+}
+
+func handleWhoisguardGetList(s *Server, _ url.Values, resp *response) {
+	var matched []*Domain
+	for _, d := range s.sortedDomains() {
+		if d.WhoisGuard {
+			matched = append(matched, d)
+		}
+	}
+
+	resp.open("WhoisguardGetListResult")
+	for _, d := range matched {
+		resp.element("Whoisguard",
+			"ID", whoisguardID(s, d),
+			"DomainName", d.Name,
+			"Created", d.Created.Format(dateLayout),
+			"Expires", d.Expires.Format(dateLayout),
+			"Status", "enabled",
+		)
+	}
+	resp.close("WhoisguardGetListResult")
+	resp.open("Paging")
+	resp.text("TotalItems", len(matched))
+	resp.text("CurrentPage", 1)
+	resp.text("PageSize", 100)
+	resp.close("Paging")
+}
+
+func handleWhoisguardDisable(s *Server, params url.Values, resp *response) {
+	for _, d := range s.sortedDomains() {
+		if d.WhoisGuard && strconv.Itoa(whoisguardID(s, d)) == params.Get("WhoisguardID") {
+			d.WhoisGuard = false
+			resp.element("WhoisguardDisableResult", "DomainName", d.Name, "IsSuccess", true)
+			return
+		}
+	}
+	resp.fail("2011170", "WhoisguardID is invalid")
+}
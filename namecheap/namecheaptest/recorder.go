@@ -0,0 +1,240 @@
+package namecheaptest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+)
+
+const sandboxEndpoint = "https://api.sandbox.namecheap.com/xml.response"
+
+// Recorder replays recorded NameCheap XML responses ("cassettes") from a
+// directory, one file per API command. When NAMECHEAP_RECORD is true it
+// instead forwards every request to the NameCheap sandbox (or
+// NAMECHEAP_ENDPOINT) with the NAMECHEAP_* credentials and writes the
+// redacted responses to the directory.
+type Recorder struct {
+	*httptest.Server
+
+	t        testing.TB
+	dir      string
+	record   bool
+	upstream string
+	options  *namecheap.ClientOptions
+}
+
+// NewRecorder starts a Recorder serving cassettes from dir, which is usually
+// "testdata". The server is closed when the test finishes.
+func NewRecorder(t testing.TB, dir string) *Recorder {
+	t.Helper()
+
+	record, _ := strconv.ParseBool(os.Getenv("NAMECHEAP_RECORD"))
+	r := &Recorder{t: t, dir: dir, record: record}
+
+	if record {
+		r.options = &namecheap.ClientOptions{
+			UserName: os.Getenv("NAMECHEAP_USER_NAME"),
+			ApiUser:  os.Getenv("NAMECHEAP_API_USER"),
+			ApiKey:   os.Getenv("NAMECHEAP_API_KEY"),
+			ClientIp: os.Getenv("NAMECHEAP_CLIENT_IP"),
+		}
+		r.upstream = sandboxEndpoint
+		if endpoint := os.Getenv("NAMECHEAP_ENDPOINT"); endpoint != "" {
+			r.upstream = endpoint
+		}
+		t.Logf("recording NameCheap responses from %s into %s", r.upstream, dir)
+	} else {
+		r.options = &namecheap.ClientOptions{
+			UserName: UserName,
+			ApiUser:  ApiUser,
+			ApiKey:   ApiKey,
+			ClientIp: ClientIp,
+		}
+	}
+
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+// Endpoint returns the URL to send the requests to.
+func (r *Recorder) Endpoint() string {
+	return r.URL + "/xml.response"
+}
+
+// ClientOptions returns the credentials to send the requests with, the
+// NAMECHEAP_* ones when recording.
+func (r *Recorder) ClientOptions() *namecheap.ClientOptions {
+	return r.options
+}
+
+// cassette returns the path of the file holding the response of a command.
+func (r *Recorder) cassette(command string) string {
+	return filepath.Join(r.dir, command+".xml")
+}
+
+func (r *Recorder) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	command := req.Form.Get("Command")
+	var (
+		body []byte
+		err  error
+	)
+	if r.record {
+		body, err = r.forward(req.Form)
+	} else {
+		body, err = os.ReadFile(r.cassette(command))
+		if errors.Is(err, os.ErrNotExist) {
+			err = fmt.Errorf("no cassette for %s, record it with NAMECHEAP_RECORD=true", command)
+		}
+	}
+	if err != nil {
+		r.t.Errorf("namecheaptest: %s: %v", command, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+// forward sends the request to the real API and stores the redacted
+// response as the cassette of the command.
+func (r *Recorder) forward(form url.Values) ([]byte, error) {
+	resp, err := http.PostForm(r.upstream, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+
+	body = Redact(body, form)
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(r.cassette(form.Get("Command")), body, 0o644); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// contactPlaceholders are the values recorded contact details are replaced
+// with, by field name. They are NameCheap's documentation example, which is
+// also the address of the fake server.
+var contactPlaceholders = map[string]string{
+	"FirstName":           "John",
+	"LastName":            "Doe",
+	"JobTitle":            "",
+	"OrganizationName":    "",
+	"Organization":        "",
+	"AddressName":         "Primary Address",
+	"Address1":            "8939 S.cross Blvd",
+	"Address2":            "",
+	"City":                "Phoenix",
+	"StateProvince":       "AZ",
+	"StateProvinceChoice": "S",
+	"PostalCode":          "85284",
+	"Zip":                 "85284",
+	"Country":             "US",
+	"Phone":               "+1.6613102107",
+	"PhoneExt":            "",
+	"Fax":                 "",
+	"EmailAddress":        "john@example.com",
+}
+
+// contactTypes prefix the contact parameters of domains.create and
+// domains.setContacts, e.g. RegistrantEmailAddress.
+var contactTypes = []string{"Registrant", "Tech", "Admin", "AuxBilling", "Billing"}
+
+// contactElement matches the elements of response bodies holding a contact
+// detail, capturing the field name and the value.
+var contactElement = regexp.MustCompile(`<(` + strings.Join(contactFieldNames(), "|") + `)>([^<]*)</`)
+
+func contactFieldNames() []string {
+	names := make([]string, 0, len(contactPlaceholders))
+	for name := range contactPlaceholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Redact replaces the credentials and client IP of a request wherever they
+// appear in body with the placeholder credentials of this package. Contact
+// details are replaced with contactPlaceholders: the values of the contact
+// elements, and the contact parameters of the request wherever they are a
+// whole element or attribute value.
+func Redact(body []byte, form url.Values) []byte {
+	replacements := map[string]string{
+		form.Get("ApiKey"):   ApiKey,
+		form.Get("ClientIp"): ClientIp,
+		form.Get("ApiUser"):  ApiUser,
+		form.Get("Username"): UserName,
+	}
+
+	// Replace longer secrets first, so one value containing another is not
+	// left partially redacted.
+	secrets := make([]string, 0, len(replacements))
+	for secret := range replacements {
+		if strings.TrimSpace(secret) != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+
+	for _, secret := range secrets {
+		body = bytes.ReplaceAll(body, []byte(secret), []byte(replacements[secret]))
+	}
+
+	// Contact values such as a country code are too short to be replaced
+	// anywhere in the body, so only whole element and attribute values are.
+	for _, contact := range contactTypes {
+		for field, placeholder := range contactPlaceholders {
+			value := form.Get(contact + field)
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+			value, placeholder := escapeXML(value), escapeXML(placeholder)
+			body = bytes.ReplaceAll(body, []byte(">"+value+"<"), []byte(">"+placeholder+"<"))
+			body = bytes.ReplaceAll(body, []byte(`="`+value+`"`), []byte(`="`+placeholder+`"`))
+		}
+	}
+
+	return contactElement.ReplaceAllFunc(body, func(element []byte) []byte {
+		field := contactElement.FindSubmatch(element)[1]
+		return []byte("<" + string(field) + ">" + escapeXML(contactPlaceholders[string(field)]) + "</")
+	})
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
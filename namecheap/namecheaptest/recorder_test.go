@@ -0,0 +1,39 @@
+package namecheaptest
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	form := url.Values{
+		"ApiKey":   {"0123456789abcdef"},
+		"ApiUser":  {"alice"},
+		"Username": {"alice"},
+		"ClientIp": {"203.0.113.7"},
+	}
+	body := []byte(`<Domain User="alice" /><Error Number="1011150">Invalid request IP: 203.0.113.7 for key 0123456789abcdef</Error>`)
+
+	got := string(Redact(body, form))
+	want := `<Domain User="testuser" /><Error Number="1011150">Invalid request IP: 127.0.0.1 for key testkey</Error>`
+	if got != want {
+		t.Errorf("unexpected redacted body:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestRedactContacts(t *testing.T) {
+	form := url.Values{
+		"Command":                {"namecheap.domains.create"},
+		"RegistrantEmailAddress": {"jane@example.org"},
+		"RegistrantCountry":      {"GB"},
+	}
+	body := []byte(`<Result Email="jane@example.org" Country="GB" Currency="GBP">` +
+		`<FirstName>Jane</FirstName><Zip>SW1A 1AA</Zip><Fax /><JobTitle>CTO &amp; founder</JobTitle></Result>`)
+
+	got := string(Redact(body, form))
+	want := `<Result Email="john@example.com" Country="US" Currency="GBP">` +
+		`<FirstName>John</FirstName><Zip>85284</Zip><Fax /><JobTitle></JobTitle></Result>`
+	if got != want {
+		t.Errorf("unexpected redacted body:\n got: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package namecheaptest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// response accumulates the XML envelope written for a single request.
+type response struct {
+	command string
+	errors  []Error
+	body    strings.Builder
+}
+
+func (r *response) fail(number string, message string) {
+	r.errors = append(r.errors, Error{Number: number, Message: message})
+}
+
+// element writes a self-closing element with the given attributes, which are
+// passed as name/value pairs.
+func (r *response) element(name string, attrs ...any) {
+	r.body.WriteString("<" + name + formatAttrs(attrs) + "/>")
+}
+
+// open writes an opening tag with the given name/value attribute pairs.
+func (r *response) open(name string, attrs ...any) {
+	r.body.WriteString("<" + name + formatAttrs(attrs) + ">")
+}
+
+func (r *response) close(name string) {
+	r.body.WriteString("</" + name + ">")
+}
+
+// text writes an element containing only character data.
+func (r *response) text(name string, value any) {
+	r.body.WriteString("<" + name + ">" + escape(fmt.Sprint(value)) + "</" + name + ">")
+}
+
+func (r *response) bytes() []byte {
+	var b bytes.Buffer
+
+	status := "OK"
+	if len(r.errors) > 0 {
+		status = "ERROR"
+	}
+
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	b.WriteString(`<ApiResponse Status="` + status + `" xmlns="http://api.namecheap.com/xml.response">`)
+	b.WriteString("<Errors>")
+	for _, e := range r.errors {
+		b.WriteString(`<Error Number="` + escape(e.Number) + `">` + escape(e.Message) + "</Error>")
+	}
+	b.WriteString("</Errors>")
+	b.WriteString("<Warnings />")
+	b.WriteString("<RequestedCommand>" + escape(strings.ToLower(r.command)) + "</RequestedCommand>")
+	if len(r.errors) == 0 {
+		b.WriteString(`<CommandResponse Type="` + escape(r.command) + `">`)
+		b.WriteString(r.body.String())
+		b.WriteString("</CommandResponse>")
+	}
+	b.WriteString("<Server>NAMECHEAPTEST</Server>")
+	b.WriteString("<GMTTimeDifference>--5:00</GMTTimeDifference>")
+	b.WriteString("<ExecutionTime>0.01</ExecutionTime>")
+	b.WriteString("</ApiResponse>")
+
+	return b.Bytes()
+}
+
+func formatAttrs(attrs []any) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(attrs); i += 2 {
+		b.WriteString(fmt.Sprintf(` %s="%s"`, attrs[i], escape(formatValue(attrs[i+1]))))
+	}
+	return b.String()
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case float64:
+		// NameCheap reports zero amounts, e.g. the premium price of a regular
+		// domain, as a bare "0".
+		if v == 0 {
+			return "0"
+		}
+		return fmt.Sprintf("%.2f", v)
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
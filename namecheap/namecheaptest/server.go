@@ -0,0 +1,421 @@
+// Package namecheaptest provides an in-memory implementation of the NameCheap
+// XML API, so the provider and the sdk package can be exercised without
+// network access or a funded sandbox account.
+package namecheaptest
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+)
+
+// Credentials accepted by the fake server.
+const (
+	UserName = "testuser"
+	ApiUser  = "testuser"
+	ApiKey   = "testkey"
+	ClientIp = "127.0.0.1"
+)
+
+// Domain is a domain owned by the fake account.
+type Domain struct {
+	Name        string
+	Created     time.Time
+	Expires     time.Time
+	AutoRenew   bool
+	IsLocked    bool
+	IsPremium   bool
+	Nameservers []string
+	Hosts       []Host
+
+	// PremiumDNS reports an active PremiumDNS subscription for the domain.
+	PremiumDNS bool
+	// OnPremiumDNS reports that the domain uses PremiumDNS rather than
+	// BasicDNS when it has no custom nameservers. Setting the default DNS
+	// switches to PremiumDNS while the subscription is active.
+	OnPremiumDNS bool
+	// WhoisGuard reports enabled privacy protection for the domain.
+	WhoisGuard bool
+}
+
+// Host is a DNS host record of a domain using NameCheap's own DNS.
+type Host struct {
+	Name    string
+	Type    string
+	Address string
+	MXPref  int
+	TTL     int
+}
+
+// Premium describes the pricing of a premium domain available to register.
+// Registering it for n years is charged the registration price, the renewal
+// price for the other n-1 years, the ICANN fee of every year and the EAP fee.
+type Premium struct {
+	RegistrationPrice float64
+	RenewalPrice      float64
+	IcannFee          float64
+	EapFee            float64
+}
+
+// Address is the account address returned by users.address.getInfo.
+type Address struct {
+	AddressId     string
+	AddressName   string
+	FirstName     string
+	LastName      string
+	Address1      string
+	Address2      string
+	City          string
+	StateProvince string
+	PostalCode    string
+	Country       string
+	Phone         string
+	EmailAddress  string
+}
+
+// Error is an API error returned in the response envelope.
+type Error struct {
+	Number  string
+	Message string
+}
+
+// Server is a fake NameCheap XML API server backed by in-memory state. All
+// methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Now returns the current time of the fake server and is used to
+	// compute expiry related fields. Defaults to time.Now.
+	Now func() time.Time
+
+	mu          sync.Mutex
+	domains     map[string]*Domain
+	unavailable map[string]bool
+	premium     map[string]Premium
+	pricing     map[string]map[string]map[int]float64
+	balance     float64
+	address     Address
+	failures    map[string][]Error
+	delays      map[string]time.Duration
+	requests    []url.Values
+	nextID      int
+}
+
+// NewServer starts a fake server with an empty account, a balance of 1000
+// and default pricing of 1 to 10 years for the com, net and org TLDs. The
+// caller must call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		Now:         time.Now,
+		domains:     map[string]*Domain{},
+		unavailable: map[string]bool{},
+		premium:     map[string]Premium{},
+		pricing:     map[string]map[string]map[int]float64{},
+		delays:      map[string]time.Duration{},
+		balance:     1000,
+		address: Address{
+			AddressId:     "0",
+			AddressName:   "Primary Address",
+			FirstName:     "John",
+			LastName:      "Doe",
+			Address1:      "8939 S.cross Blvd",
+			City:          "Phoenix",
+			StateProvince: "AZ",
+			PostalCode:    "85284",
+			Country:       "US",
+			Phone:         "+1.6613102107",
+			EmailAddress:  "john@example.com",
+		},
+		failures: map[string][]Error{},
+		nextID:   1,
+	}
+	for _, tld := range []string{"com", "net", "org"} {
+		for n := 1; n <= 10; n++ {
+			register := 12.98
+			if n == 1 {
+				register = 8.88
+			}
+			s.SetPrice("register", tld, n, register)
+			s.SetPrice("renew", tld, n, 12.98)
+			s.SetPrice("reactivate", tld, n, 12.98)
+		}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Endpoint returns the URL to use as the provider endpoint.
+func (s *Server) Endpoint() string {
+	return s.URL + "/xml.response"
+}
+
+// ClientOptions returns the credentials accepted by the fake server.
+func (s *Server) ClientOptions() *namecheap.ClientOptions {
+	return &namecheap.ClientOptions{
+		UserName: UserName,
+		ApiUser:  ApiUser,
+		ApiKey:   ApiKey,
+		ClientIp: ClientIp,
+	}
+}
+
+// AddDomain adds a domain to the fake account, replacing any domain with the
+// same name.
+func (s *Server) AddDomain(d Domain) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d.Name = strings.ToLower(d.Name)
+	if d.Created.IsZero() {
+		d.Created = s.Now()
+	}
+	if d.Expires.IsZero() {
+		d.Expires = d.Created.AddDate(1, 0, 0)
+	}
+	s.domains[d.Name] = &d
+}
+
+// Domain returns a copy of a domain owned by the fake account.
+func (s *Server) Domain(name string) (Domain, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[strings.ToLower(name)]
+	if !ok {
+		return Domain{}, false
+	}
+	c := *d
+	c.Nameservers = append([]string(nil), d.Nameservers...)
+	c.Hosts = append([]Host(nil), d.Hosts...)
+	return c, true
+}
+
+// SetExpiry changes the expiry date of an owned domain.
+func (s *Server) SetExpiry(name string, expires time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.domains[strings.ToLower(name)]; ok {
+		d.Expires = expires
+	}
+}
+
+// SetUnavailable marks a domain as taken, so domains.check reports it as not
+// available and domains.create fails.
+func (s *Server) SetUnavailable(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unavailable[strings.ToLower(name)] = true
+}
+
+// SetNameservers sets the custom nameservers a registered domain reports.
+func (s *Server) SetNameservers(name string, nameservers ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.domains[strings.ToLower(name)]; ok {
+		d.Nameservers = nameservers
+	}
+}
+
+// SetAutoRenew sets the auto-renew flag of a registered domain, as done in
+// the NameCheap dashboard.
+func (s *Server) SetAutoRenew(name string, autoRenew bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.domains[strings.ToLower(name)]; ok {
+		d.AutoRenew = autoRenew
+	}
+}
+
+// SetWhoisGuard enables or disables the privacy protection of a registered
+// domain.
+func (s *Server) SetWhoisGuard(name string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.domains[strings.ToLower(name)]; ok {
+		d.WhoisGuard = enabled
+	}
+}
+
+// SetPremiumDNS sets whether a registered domain has an active PremiumDNS
+// subscription.
+func (s *Server) SetPremiumDNS(name string, active bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.domains[strings.ToLower(name)]; ok {
+		d.PremiumDNS = active
+		d.OnPremiumDNS = d.OnPremiumDNS && active
+	}
+}
+
+// SetPremium marks a domain as a premium name with the given pricing.
+func (s *Server) SetPremium(name string, p Premium) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.premium[strings.ToLower(name)] = p
+}
+
+// SetPrice sets the yearly price of an action ("register", "renew" or
+// "reactivate") for a TLD and duration in years. Like NameCheap's
+// "MULTIPLE" prices, it is charged once per year, together with the ICANN
+// fee.
+func (s *Server) SetPrice(action string, tld string, years int, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	action, tld = strings.ToLower(action), strings.ToLower(tld)
+	if s.pricing[action] == nil {
+		s.pricing[action] = map[string]map[int]float64{}
+	}
+	if s.pricing[action][tld] == nil {
+		s.pricing[action][tld] = map[int]float64{}
+	}
+	s.pricing[action][tld][years] = price
+}
+
+// SetBalance sets the account balance used to pay for orders.
+func (s *Server) SetBalance(balance float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.balance = balance
+}
+
+// Balance returns the current account balance.
+func (s *Server) Balance() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.balance
+}
+
+// SetAddress replaces the account address returned by users.address.getInfo.
+func (s *Server) SetAddress(a Address) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.address = a
+}
+
+// FailNext makes the next call of command (e.g. "namecheap.domains.create")
+// return the given API error. Errors queue up when called repeatedly.
+func (s *Server) FailNext(command string, number string, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[command] = append(s.failures[command], Error{Number: number, Message: message})
+}
+
+// Delay makes the server answer command only after d, once it has processed
+// it, as when the response of NameCheap is lost to a timeout.
+func (s *Server) Delay(command string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delays[command] = d
+}
+
+// Requests returns the parameters of every request received so far.
+func (s *Server) Requests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]url.Values(nil), s.requests...)
+}
+
+// Commands returns the command names of every request received so far.
+func (s *Server) Commands() []string {
+	var commands []string
+	for _, r := range s.Requests() {
+		commands = append(commands, r.Get("Command"))
+	}
+	return commands
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, delay := s.handle(r.Form)
+	time.Sleep(delay)
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+// handle processes a request and returns the response body, along with the
+// delay to send it after.
+func (s *Server) handle(params url.Values) ([]byte, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, params)
+	command := params.Get("Command")
+
+	resp := &response{command: command}
+	switch {
+	case params.Get("ApiUser") != ApiUser || params.Get("ApiKey") != ApiKey:
+		resp.fail("1011102", "Parameter APIKey is invalid")
+	case params.Get("ClientIp") != ClientIp:
+		resp.fail("1011150", fmt.Sprintf("Parameter RequestIP is invalid: %s is not whitelisted", params.Get("ClientIp")))
+	case len(s.failures[command]) > 0:
+		e := s.failures[command][0]
+		s.failures[command] = s.failures[command][1:]
+		resp.fail(e.Number, e.Message)
+	default:
+		handler, ok := handlers[command]
+		if !ok {
+			resp.fail("1010101", fmt.Sprintf("Parameter Command is invalid: %s", command))
+			break
+		}
+		handler(s, params, resp)
+	}
+
+	return resp.bytes(), s.delays[command]
+}
+
+func (s *Server) id() int {
+	s.nextID++
+	return s.nextID
+}
+
+// cost returns the amount charged for an action over years, that is the
+// yearly price and ICANN fee times the years, rounded to cents.
+func (s *Server) cost(action string, tld string, years int) (float64, bool) {
+	p, ok := s.pricing[action][tld][years]
+	return math.Round((p+icannFee)*float64(years)*100) / 100, ok
+}
+
+func (s *Server) isExpired(d *Domain) bool {
+	return d.Expires.Before(s.Now())
+}
+
+func (s *Server) sortedDomains() []*Domain {
+	names := make([]string, 0, len(s.domains))
+	for name := range s.domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]*Domain, 0, len(names))
+	for _, name := range names {
+		list = append(list, s.domains[name])
+	}
+	return list
+}
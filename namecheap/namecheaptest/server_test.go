@@ -0,0 +1,156 @@
+package namecheaptest_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
+)
+
+func clientOf(srv *namecheaptest.Server) *sdk.Client {
+	client := sdk.NewClient(srv.ClientOptions())
+	client.BaseURL = srv.Endpoint()
+	return client
+}
+
+func TestServerRegisterLifecycle(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	client := clientOf(srv)
+
+	check, err := sdk.DomainsAvailable(client, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.Result.Available || !check.Result.Price.IsZero() {
+		t.Fatalf("unexpected check result: %+v", check.Result)
+	}
+
+	pricing, err := sdk.UserGetPricing(client, "register", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := pricing.Result.ProductCategory.Price[0].Total(); err != nil || got.String() != "9.06 USD" {
+		t.Fatalf("expected register price 9.06 USD, got %s (%v)", got, err)
+	}
+
+	addr, err := sdk.UserAddrGetInfo(client, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.DomainsCreate(client, "example.com", "1", "ns1.example.net,ns2.example.net", addr, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Balance(); math.Abs(got-(1000-9.06)) > 0.001 {
+		t.Fatalf("expected balance to be charged, got %f", got)
+	}
+
+	info, err := sdk.DomainsGetInfo(client, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := *info.DomainDNSGetListResult.DnsDetails.Nameservers; len(got) != 2 || got[0] != "ns1.example.net" {
+		t.Fatalf("unexpected nameservers: %v", got)
+	}
+
+	if _, err := sdk.DomainsDNSSetCustom(client, "example.com", []string{"ns3.example.net", "ns4.example.net"}); err != nil {
+		t.Fatal(err)
+	}
+	d, _ := srv.Domain("example.com")
+	if strings.Join(d.Nameservers, ",") != "ns3.example.net,ns4.example.net" {
+		t.Fatalf("nameservers not updated: %v", d.Nameservers)
+	}
+
+	set, err := sdk.DomainsDNSSetDefault(client, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*set.DomainDNSSetDefaultResult.Updated {
+		t.Fatal("expected default DNS to be set")
+	}
+	if d, _ := srv.Domain("example.com"); len(d.Nameservers) != 0 {
+		t.Fatalf("expected NameCheap DNS, got nameservers %v", d.Nameservers)
+	}
+
+	renew, err := sdk.DomainsRenew(client, "example.com", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renew.Result.Renew {
+		t.Fatal("expected renewal to succeed")
+	}
+}
+
+func TestServerReactivateExpired(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	client := clientOf(srv)
+
+	srv.AddDomain(namecheaptest.Domain{
+		Name:    "expired.com",
+		Created: time.Now().AddDate(-2, 0, 0),
+		Expires: time.Now().AddDate(0, 0, -5),
+	})
+
+	if _, err := sdk.DomainsRenew(client, "expired.com", "1"); err == nil {
+		t.Fatal("expected renewal of an expired domain to fail")
+	}
+
+	list, err := sdk.DomainsGetList(client, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*(*list.Domains)[0].IsExpired {
+		t.Fatal("expected domain to be reported as expired")
+	}
+
+	resp, err := sdk.DomainsReactivate(client, "expired.com", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Result.IsSuccess {
+		t.Fatal("expected reactivation to succeed")
+	}
+	if d, _ := srv.Domain("expired.com"); !d.Expires.After(time.Now()) {
+		t.Fatalf("expected expiry in the future, got %s", d.Expires)
+	}
+}
+
+func TestServerScriptedFailures(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	client := clientOf(srv)
+
+	srv.SetUnavailable("taken.com")
+	check, err := sdk.DomainsAvailable(client, "taken.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Result.Available {
+		t.Fatal("expected taken.com to be unavailable")
+	}
+
+	srv.FailNext("namecheap.domains.check", "3050900", "Unknown response from provider")
+	if _, err := sdk.DomainsAvailable(client, "example.com"); err == nil || !strings.Contains(err.Error(), "3050900") {
+		t.Fatalf("expected scripted error, got %v", err)
+	}
+	if _, err := sdk.DomainsAvailable(client, "example.com"); err != nil {
+		t.Fatalf("expected scripted error to be consumed, got %v", err)
+	}
+
+	srv.SetBalance(1)
+	addr, err := sdk.UserAddrGetInfo(client, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.DomainsCreate(client, "example.com", "1", "", addr, nil); err == nil {
+		t.Fatal("expected creation to fail with insufficient funds")
+	}
+
+	if _, err := sdk.DomainsGetInfo(client, "missing.com"); err == nil || !strings.Contains(err.Error(), "Domain is invalid") {
+		t.Fatalf("expected domain is invalid error, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.SetTypable                    = nameserversType{}
+	_ basetypes.SetValuableWithSemanticEquals = nameserversValue{}
+)
+
+// nameserversType is a set of nameserver hostnames, which NameCheap may
+// report in a different case than they were configured.
+type nameserversType struct {
+	basetypes.SetType
+}
+
+func newNameserversType() nameserversType {
+	return nameserversType{SetType: basetypes.SetType{ElemType: types.StringType}}
+}
+
+func (t nameserversType) Equal(o attr.Type) bool {
+	other, ok := o.(nameserversType)
+	if !ok {
+		return false
+	}
+	return t.SetType.Equal(other.SetType)
+}
+
+func (t nameserversType) String() string {
+	return "nameserversType"
+}
+
+func (t nameserversType) ValueFromSet(_ context.Context, in basetypes.SetValue) (basetypes.SetValuable, diag.Diagnostics) {
+	return nameserversValue{SetValue: in}, nil
+}
+
+func (t nameserversType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	value, err := t.SetType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	set, ok := value.(basetypes.SetValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", value)
+	}
+	return nameserversValue{SetValue: set}, nil
+}
+
+func (t nameserversType) ValueType(_ context.Context) attr.Value {
+	return nameserversValue{}
+}
+
+// nameserversValue is the value of a nameserversType.
+type nameserversValue struct {
+	basetypes.SetValue
+}
+
+func newNameserversValue(hostnames []string) nameserversValue {
+	elements := []attr.Value{}
+	for _, x := range hostnames {
+		elements = append(elements, types.StringValue(x))
+	}
+	return nameserversValue{SetValue: types.SetValueMust(types.StringType, elements)}
+}
+
+func newNameserversUnknown() nameserversValue {
+	return nameserversValue{SetValue: types.SetUnknown(types.StringType)}
+}
+
+func newNameserversNull() nameserversValue {
+	return nameserversValue{SetValue: types.SetNull(types.StringType)}
+}
+
+func (v nameserversValue) Equal(o attr.Value) bool {
+	other, ok := o.(nameserversValue)
+	if !ok {
+		return false
+	}
+	return v.SetValue.Equal(other.SetValue)
+}
+
+func (v nameserversValue) Type(_ context.Context) attr.Type {
+	return newNameserversType()
+}
+
+// SetSemanticEquals reports whether both sets hold the same hostnames,
+// ignoring case and trailing dots.
+func (v nameserversValue) SetSemanticEquals(_ context.Context, o basetypes.SetValuable) (bool, diag.Diagnostics) {
+	other, ok := o.(nameserversValue)
+	if !ok {
+		return false, nil
+	}
+	return sameNameservers(v.hostnames(), other.hostnames()), nil
+}
+
+// hostnames returns the known hostnames of the set.
+func (v nameserversValue) hostnames() []string {
+	var hostnames []string
+	for _, x := range v.Elements() {
+		if s, ok := x.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			hostnames = append(hostnames, s.ValueString())
+		}
+	}
+	return hostnames
+}
+
+// sameNameservers reports whether a and b hold the same hostnames, ignoring
+// order, case and trailing dots.
+func sameNameservers(a []string, b []string) bool {
+	return strings.Join(normalizeNameservers(a), ",") == strings.Join(normalizeNameservers(b), ",")
+}
+
+func normalizeNameservers(hostnames []string) []string {
+	seen := map[string]bool{}
+	var normalized []string
+	for _, x := range hostnames {
+		x = strings.ToLower(strings.TrimSuffix(x, "."))
+		if !seen[x] {
+			seen[x] = true
+			normalized = append(normalized, x)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
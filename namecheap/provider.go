@@ -2,16 +2,26 @@ package namecheap
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
-	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
 )
 
 // Ensure the implementation satisfies the expected interfaces
@@ -19,19 +29,50 @@ var (
 	_ provider.Provider = &namecheapProvider{}
 )
 
-type namecheapProvider struct{}
+type namecheapProvider struct {
+	// version is the provider version, sent in the User-Agent.
+	version string
+}
 
 type namecheapProviderModel struct {
-	UserName   types.String `tfsdk:"user_name"`
-	ApiUser    types.String `tfsdk:"api_user"`
-	ApiKey     types.String `tfsdk:"api_key"`
-	ClientIp   types.String `tfsdk:"client_ip"`
-	UseSandbox types.Bool   `tfsdk:"use_sandbox"`
+	UserName    types.String `tfsdk:"user_name"`
+	ApiUser     types.String `tfsdk:"api_user"`
+	ApiKey      types.String `tfsdk:"api_key"`
+	ClientIp    types.String `tfsdk:"client_ip"`
+	UseSandbox  types.Bool   `tfsdk:"use_sandbox"`
+	Environment types.String `tfsdk:"environment"`
+	Endpoint    types.String `tfsdk:"endpoint"`
+	Accounts    types.Map    `tfsdk:"accounts"`
+
+	Profile               types.String `tfsdk:"profile"`
+	SharedCredentialsFile types.String `tfsdk:"shared_credentials_file"`
+	CredentialProcess     types.String `tfsdk:"credential_process"`
+	ClientIpResolverURL   types.String `tfsdk:"client_ip_resolver_url"`
+
+	HttpProxy          types.String `tfsdk:"http_proxy"`
+	RequestTimeout     types.String `tfsdk:"request_timeout"`
+	CABundle           types.String `tfsdk:"ca_bundle"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
 }
 
+type namecheapAccountModel struct {
+	UserName types.String `tfsdk:"user_name"`
+	ApiUser  types.String `tfsdk:"api_user"`
+	ApiKey   types.String `tfsdk:"api_key"`
+}
+
+const (
+	ENVIRONMENT_PRODUCTION string = "production"
+	ENVIRONMENT_SANDBOX    string = "sandbox"
+)
+
 // New is a helper function to simplify provider server
-func New() provider.Provider {
-	return &namecheapProvider{}
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &namecheapProvider{
+			version: version,
+		}
+	}
 }
 
 // Metadata returns the provider type name.
@@ -61,14 +102,106 @@ func (p *namecheapProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 				Sensitive:   true,
 			},
 			"client_ip": schema.StringAttribute{
-				Description: "Client IP address. May also be provided via NAMECHEAP_CLIENT_IP environment variable.",
-				Optional:    true,
+				Description: "Client IP address, which must be whitelisted for API access in NameCheap. Set it to " +
+					"auto to detect the IPv4 address the provider connects from with client_ip_resolver_url. May " +
+					"also be provided via NAMECHEAP_CLIENT_IP environment variable.",
+				Optional: true,
+			},
+			"client_ip_resolver_url": schema.StringAttribute{
+				Description: "URL of the service that replies with the IPv4 address of the caller as plain text, " +
+					"used when client_ip is auto. The default is " + defaultClientIpResolverURL + ". May also be " +
+					"provided via NAMECHEAP_CLIENT_IP_RESOLVER_URL environment variable.",
+				Optional: true,
 			},
 			"use_sandbox": schema.BoolAttribute{
-				Description: "Whether to use sandbox API endpoints. May also be provided via NAMECHEAP_USE_SANDBOX " +
+				Description: "Whether to use sandbox API endpoints. The default is false, which uses the production " +
+					"API. May also be provided via NAMECHEAP_USE_SANDBOX environment variable, as true/false, " +
+					"1/0, yes/no or on/off.",
+				Optional: true,
+			},
+			"environment": schema.StringAttribute{
+				Description: "The NameCheap API environment, either production or sandbox. It is an alternative to " +
+					"use_sandbox and must agree with it when both are set. May also be provided via " +
+					"NAMECHEAP_ENVIRONMENT environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(ENVIRONMENT_PRODUCTION, ENVIRONMENT_SANDBOX),
+				},
+			},
+			"endpoint": schema.StringAttribute{
+				Description: "Override the base URL of the NameCheap XML API, e.g. to point the provider at a local " +
+					"mock server. Takes precedence over use_sandbox. May also be provided via NAMECHEAP_ENDPOINT " +
+					"environment variable.",
+				Optional: true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "Name of the profile of the credentials file to read user_name, api_user, api_key and " +
+					"client_ip from, when they are neither configured nor set in the environment. The default is " +
+					"default, which may be missing from the file when no value is read from it. May also be " +
+					"provided via NAMECHEAP_PROFILE environment variable.",
+				Optional: true,
+			},
+			"shared_credentials_file": schema.StringAttribute{
+				Description: "Path of the INI credentials file with the profiles. The default is " +
+					"~/.namecheap/credentials, which is ignored if it does not exist. May also be provided via " +
+					"NAMECHEAP_SHARED_CREDENTIALS_FILE environment variable.",
+				Optional: true,
+			},
+			"credential_process": schema.StringAttribute{
+				Description: "Command that prints a JSON object with user_name, api_user, api_key and client_ip, " +
+					"e.g. to read the API key from a secrets manager. It is run every time the provider is " +
+					"configured, and its values take precedence over the credentials file but not over the " +
+					"configuration or environment variables. May also be provided via NAMECHEAP_CREDENTIAL_PROCESS " +
 					"environment variable.",
 				Optional: true,
 			},
+			"http_proxy": schema.StringAttribute{
+				Description: "URL of the proxy to send the requests through. The default is to honour the " +
+					"HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables. May also be provided via " +
+					"NAMECHEAP_HTTP_PROXY environment variable.",
+				Optional: true,
+			},
+			"request_timeout": schema.StringAttribute{
+				Description: "Time limit of every request as a Go duration, e.g. 30s. The default is no time limit. " +
+					"Registrations, renewals and reactivations that time out are not sent again, as NameCheap may " +
+					"have processed them. May also be provided via NAMECHEAP_REQUEST_TIMEOUT environment variable.",
+				Optional: true,
+			},
+			"ca_bundle": schema.StringAttribute{
+				Description: "Path of a file with PEM encoded CA certificates to trust in addition to the system " +
+					"ones, e.g. for a TLS intercepting proxy. May also be provided via NAMECHEAP_CA_BUNDLE " +
+					"environment variable.",
+				Optional: true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Whether to skip the verification of TLS certificates, only meant for mock servers. " +
+					"The default is false. May also be provided via NAMECHEAP_INSECURE_SKIP_VERIFY environment " +
+					"variable.",
+				Optional: true,
+			},
+			"accounts": schema.MapNestedAttribute{
+				Description: "Credentials of additional NameCheap accounts, keyed by a name that resources select " +
+					"with their account attribute. The client_ip, use_sandbox and endpoint settings are shared with " +
+					"the top-level credentials, which become optional when accounts are configured.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"user_name": schema.StringAttribute{
+							Description: "A registered user name for the NameCheap account.",
+							Required:    true,
+						},
+						"api_user": schema.StringAttribute{
+							Description: "A registered api user for the NameCheap account.",
+							Required:    true,
+						},
+						"api_key": schema.StringAttribute{
+							Description: "The NameCheap API key of the account.",
+							Required:    true,
+							Sensitive:   true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -123,6 +256,16 @@ func (p *namecheapProvider) Configure(ctx context.Context, req provider.Configur
 		)
 	}
 
+	if config.ClientIpResolverURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_ip_resolver_url"),
+			"Unknown client_ip_resolver_url",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap client_ip_resolver_url. Set the value statically in the configuration, or use the "+
+				"NAMECHEAP_CLIENT_IP_RESOLVER_URL environment variable.",
+		)
+	}
+
 	if config.UseSandbox.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("use_sandbox"),
@@ -133,12 +276,255 @@ func (p *namecheapProvider) Configure(ctx context.Context, req provider.Configur
 		)
 	}
 
+	if config.Environment.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("environment"),
+			"Unknown environment",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap environment. Set the value statically in the configuration, or use the "+
+				"NAMECHEAP_ENVIRONMENT environment variable.",
+		)
+	}
+
+	if config.Endpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unknown endpoint",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap endpoint. Set the value statically in the configuration, or use the NAMECHEAP_ENDPOINT "+
+				"environment variable.",
+		)
+	}
+
+	if config.Profile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unknown profile",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap profile. Set the value statically in the configuration, or use the NAMECHEAP_PROFILE "+
+				"environment variable.",
+		)
+	}
+
+	if config.SharedCredentialsFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("shared_credentials_file"),
+			"Unknown shared_credentials_file",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap shared_credentials_file. Set the value statically in the configuration, or use the "+
+				"NAMECHEAP_SHARED_CREDENTIALS_FILE environment variable.",
+		)
+	}
+
+	if config.CredentialProcess.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("credential_process"),
+			"Unknown credential_process",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap credential_process. Set the value statically in the configuration, or use the "+
+				"NAMECHEAP_CREDENTIAL_PROCESS environment variable.",
+		)
+	}
+
+	if config.HttpProxy.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("http_proxy"),
+			"Unknown http_proxy",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap http_proxy. Set the value statically in the configuration, or use the "+
+				"NAMECHEAP_HTTP_PROXY environment variable.",
+		)
+	}
+
+	if config.RequestTimeout.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("request_timeout"),
+			"Unknown request_timeout",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap request_timeout. Set the value statically in the configuration, or use the "+
+				"NAMECHEAP_REQUEST_TIMEOUT environment variable.",
+		)
+	}
+
+	if config.CABundle.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_bundle"),
+			"Unknown ca_bundle",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap ca_bundle. Set the value statically in the configuration, or use the "+
+				"NAMECHEAP_CA_BUNDLE environment variable.",
+		)
+	}
+
+	if config.InsecureSkipVerify.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("insecure_skip_verify"),
+			"Unknown insecure_skip_verify",
+			"The provider cannot create the NameCheap API client as there is an unknown configuration value for the"+
+				"NameCheap insecure_skip_verify. Set the value statically in the configuration, or use the "+
+				"NAMECHEAP_INSECURE_SKIP_VERIFY environment variable.",
+		)
+	}
+
+	if config.Accounts.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("accounts"),
+			"Unknown accounts",
+			"The provider cannot create the NameCheap API clients as there is an unknown configuration value for "+
+				"the NameCheap accounts. Set the value statically in the configuration.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	transport := transportOptions{
+		UserAgent: fmt.Sprintf("terraform-provider-st-namecheap/%s", p.version),
+		Trace:     newTraceContext(ctx),
+	}
+	if !config.HttpProxy.IsNull() {
+		transport.Proxy = config.HttpProxy.ValueString()
+	} else {
+		transport.Proxy = os.Getenv("NAMECHEAP_HTTP_PROXY")
+	}
+	if !config.CABundle.IsNull() {
+		transport.CABundle = config.CABundle.ValueString()
+	} else {
+		transport.CABundle = os.Getenv("NAMECHEAP_CA_BUNDLE")
+	}
+
+	var requestTimeout string
+	if !config.RequestTimeout.IsNull() {
+		requestTimeout = config.RequestTimeout.ValueString()
+	} else {
+		requestTimeout = os.Getenv("NAMECHEAP_REQUEST_TIMEOUT")
+	}
+	if requestTimeout != "" {
+		timeout, err := time.ParseDuration(requestTimeout)
+		if err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid NameCheap request_timeout",
+				fmt.Sprintf("The provider cannot create the NameCheap API client as the request_timeout %q is "+
+					"not a positive duration, e.g. 30s or 2m.", requestTimeout),
+			)
+		}
+		transport.Timeout = timeout
+	}
+
+	if !config.InsecureSkipVerify.IsNull() {
+		transport.InsecureSkipVerify = config.InsecureSkipVerify.ValueBool()
+	} else if v := os.Getenv("NAMECHEAP_INSECURE_SKIP_VERIFY"); v != "" {
+		var ok bool
+		if transport.InsecureSkipVerify, ok = parseBoolSpelling(v); !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("insecure_skip_verify"),
+				"Invalid NameCheap insecure_skip_verify",
+				fmt.Sprintf("The provider cannot create the NameCheap API client as the "+
+					"NAMECHEAP_INSECURE_SKIP_VERIFY environment variable is %q, which is not a boolean. Use "+
+					"true/false, 1/0, yes/no or on/off.", v),
+			)
+		}
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Default values to environment variables, but override
-	// with Terraform configuration value if set.
+	httpClient, err := newHTTPClient(transport)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid NameCheap HTTP settings",
+			fmt.Sprintf("The provider cannot create the NameCheap API client: %s", err),
+		)
+		return
+	}
+
+	accounts := map[string]namecheapAccountModel{}
+	if !config.Accounts.IsNull() {
+		resp.Diagnostics.Append(config.Accounts.ElementsAs(ctx, &accounts, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	for name, account := range accounts {
+		for attr, value := range map[string]types.String{
+			"user_name": account.UserName,
+			"api_user":  account.ApiUser,
+			"api_key":   account.ApiKey,
+		} {
+			if value.IsUnknown() || value.ValueString() == "" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("accounts").AtMapKey(name).AtName(attr),
+					"Missing NameCheap account "+attr,
+					fmt.Sprintf("The provider cannot create the NameCheap API client of account [%s] as there is "+
+						"an unknown or empty value for its %s. Set the value statically in the configuration.",
+						name, attr),
+				)
+			}
+		}
+	}
+
+	// The credentials file is only required when a file is given, and the
+	// profile when it is given. Otherwise the default profile of the default
+	// file is used if it exists, and only reported missing when needed.
+	profileName, credentialsFile := defaultProfile, defaultCredentialsFile
+	profileRequested, fileRequested := false, false
+	if !config.Profile.IsNull() {
+		profileName, profileRequested = config.Profile.ValueString(), true
+	} else if v := os.Getenv("NAMECHEAP_PROFILE"); v != "" {
+		profileName, profileRequested = v, true
+	}
+	if !config.SharedCredentialsFile.IsNull() {
+		credentialsFile, fileRequested = config.SharedCredentialsFile.ValueString(), true
+	} else if v := os.Getenv("NAMECHEAP_SHARED_CREDENTIALS_FILE"); v != "" {
+		credentialsFile, fileRequested = v, true
+	}
+
+	var profileErr error
+	profile, err := loadCredentialsProfile(credentialsFile, profileName)
+	switch {
+	case err == nil:
+	case !profileRequested && !fileRequested:
+		// The default file is optional, whatever keeps it from being read.
+		profile = &credentialsProfile{}
+		if !errors.Is(err, fs.ErrNotExist) {
+			profileErr = err
+			tflog.Warn(ctx, "Ignoring the default NameCheap credentials file", map[string]interface{}{"error": err.Error()})
+		}
+	case errors.Is(err, errProfileNotFound) && !profileRequested:
+		profile, profileErr = &credentialsProfile{}, err
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Invalid NameCheap credentials profile",
+			fmt.Sprintf("The provider cannot read the profile [%s] of the credentials file: %s", profileName, err),
+		)
+		return
+	}
+
+	var credentialProcess string
+	if !config.CredentialProcess.IsNull() {
+		credentialProcess = config.CredentialProcess.ValueString()
+	} else {
+		credentialProcess = os.Getenv("NAMECHEAP_CREDENTIAL_PROCESS")
+	}
+	if credentialProcess != "" {
+		processed, err := runCredentialProcess(ctx, credentialProcess)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credential_process"),
+				"NameCheap credential_process failed",
+				fmt.Sprintf("The provider cannot read the NameCheap API credentials from credential_process: %s", err),
+			)
+			return
+		}
+		profile = profile.merge(processed)
+	}
+
+	// Default values to environment variables, then to the credential process
+	// and the profile of the credentials file, but override with Terraform
+	// configuration value if set.
 	var (
 		userName,
 		apiUser,
@@ -148,59 +534,64 @@ func (p *namecheapProvider) Configure(ctx context.Context, req provider.Configur
 
 	if !config.UserName.IsNull() {
 		userName = config.UserName.ValueString()
-	} else {
-		userName = os.Getenv("NAMECHEAP_USER_NAME")
+	} else if userName = os.Getenv("NAMECHEAP_USER_NAME"); userName == "" {
+		userName = profile.UserName
+	}
+	if !config.ApiUser.IsNull() {
+		apiUser = config.ApiUser.ValueString()
+	} else if apiUser = os.Getenv("NAMECHEAP_API_USER"); apiUser == "" {
+		apiUser = profile.ApiUser
+	}
+	if !config.ApiKey.IsNull() {
+		apiKey = config.ApiKey.ValueString()
+	} else if apiKey = os.Getenv("NAMECHEAP_API_KEY"); apiKey == "" {
+		apiKey = profile.ApiKey
 	}
-	if userName == "" {
+
+	// The top-level credentials may be left out entirely when accounts are
+	// configured, but not partially.
+	hasDefault := len(accounts) == 0 || userName != "" || apiUser != "" || apiKey != ""
+
+	if hasDefault && userName == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("user_name"),
 			"Missing NameCheap API user_name",
 			"The provider cannot create the NameCheap API client as there is a "+
 				"missing or empty value for the NameCheap API user_name. Set the "+
-				"user_name value in the configuration or use the NAMECHEAP_USER_NAME "+
-				"environment variable. If either is already set, ensure the value "+
-				"is not empty.",
+				"user_name value in the configuration, use the NAMECHEAP_USER_NAME "+
+				"environment variable, or provide it with credential_process or the "+
+				"credentials file. If any is already set, ensure the value is not empty.",
 		)
 	}
 
-	if !config.ApiUser.IsNull() {
-		apiUser = config.ApiUser.ValueString()
-	} else {
-		apiUser = os.Getenv("NAMECHEAP_API_USER")
-	}
-	if apiUser == "" {
+	if hasDefault && apiUser == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("api_user"),
 			"Missing NameCheap API access key",
 			"The provider cannot create the NameCheap API client as there is a "+
 				"missing or empty value for the NameCheap API api_user. Set the "+
-				"api_user value in the configuration or use the NAMECHEAP_API_USER "+
-				"environment variable. If either is already set, ensure the value "+
-				"is not empty.",
+				"api_user value in the configuration, use the NAMECHEAP_API_USER "+
+				"environment variable, or provide it with credential_process or the "+
+				"credentials file. If any is already set, ensure the value is not empty.",
 		)
 	}
 
-	if !config.ApiKey.IsNull() {
-		apiKey = config.ApiKey.ValueString()
-	} else {
-		apiKey = os.Getenv("NAMECHEAP_API_KEY")
-	}
-	if apiKey == "" {
+	if hasDefault && apiKey == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("api_key"),
 			"Missing NameCheap secret key",
 			"The provider cannot create the NameCheap API client as there is a "+
 				"missing or empty value for the NameCheap API api_key. Set the "+
-				"api_key value in the configuration or use the NAMECHEAP_API_KEY "+
-				"environment variable. If either is already set, ensure the value "+
-				"is not empty.",
+				"api_key value in the configuration, use the NAMECHEAP_API_KEY "+
+				"environment variable, or provide it with credential_process or the "+
+				"credentials file. If any is already set, ensure the value is not empty.",
 		)
 	}
 
 	if !config.ClientIp.IsNull() {
 		clientIp = config.ClientIp.ValueString()
-	} else {
-		clientIp = os.Getenv("NAMECHEAP_CLIENT_IP")
+	} else if clientIp = os.Getenv("NAMECHEAP_CLIENT_IP"); clientIp == "" {
+		clientIp = profile.ClientIp
 	}
 	if clientIp == "" {
 		resp.Diagnostics.AddAttributeError(
@@ -208,26 +599,101 @@ func (p *namecheapProvider) Configure(ctx context.Context, req provider.Configur
 			"Missing NameCheap secret key",
 			"The provider cannot create the NameCheap API client as there is a "+
 				"missing or empty value for the NameCheap API client_ip. Set the "+
-				"client_ip value in the configuration or use the NAMECHEAP_CLIENT_IP "+
-				"environment variable. If either is already set, ensure the value "+
-				"is not empty.",
+				"client_ip value in the configuration, use the NAMECHEAP_CLIENT_IP "+
+				"environment variable, or provide it with credential_process or the "+
+				"credentials file. If any is already set, ensure the value is not empty.",
 		)
 	}
 
-	var useSandbox bool
-	if !config.UseSandbox.IsNull() {
-		useSandbox = config.UseSandbox.ValueBool()
-	} else {
-		var err error
-		useSandbox, err = strconv.ParseBool(os.Getenv("NAMECHEAP_USE_SANDBOX"))
+	// The default profile is missing or unreadable and would have been used.
+	if profileErr != nil && (clientIp == "" || hasDefault && (userName == "" || apiUser == "" || apiKey == "")) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Invalid NameCheap credentials profile",
+			fmt.Sprintf("The provider cannot read the profile [%s] of the credentials file: %s", profileName, profileErr),
+		)
+	}
+
+	var clientIpResolverURL string
+	if clientIp == CLIENT_IP_AUTO {
+		if !config.ClientIpResolverURL.IsNull() {
+			clientIpResolverURL = config.ClientIpResolverURL.ValueString()
+		} else if clientIpResolverURL = os.Getenv("NAMECHEAP_CLIENT_IP_RESOLVER_URL"); clientIpResolverURL == "" {
+			clientIpResolverURL = defaultClientIpResolverURL
+		}
+
+		ip, err := resolveClientIp(ctx, httpClient, clientIpResolverURL)
 		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("client_ip"),
+				"Failed to detect NameCheap client_ip",
+				fmt.Sprintf("The provider cannot detect the client_ip with %s: %s. Set client_ip_resolver_url to "+
+					"a reachable service, or set client_ip to the IP address instead of auto.",
+					clientIpResolverURL, err),
+			)
+		} else {
+			tflog.Info(ctx, fmt.Sprintf("detected client_ip %s with %s", ip, clientIpResolverURL))
+			clientIp = ip
+		}
+	}
+
+	// The sandbox is used when either use_sandbox or environment ask for it,
+	// but the two must not disagree, so that a run never mixes production and
+	// sandbox settings.
+	var sandbox types.Bool
+	if !config.UseSandbox.IsNull() {
+		sandbox = config.UseSandbox
+	} else if v := os.Getenv("NAMECHEAP_USE_SANDBOX"); v != "" {
+		if b, ok := parseBoolSpelling(v); ok {
+			sandbox = types.BoolValue(b)
+		} else {
 			resp.Diagnostics.AddAttributeError(
 				path.Root("use_sandbox"),
-				"Missing NameCheap use_sandbox",
-				"The provider cannot create the NameCheap API client as there is a missing or empty value for the "+
-					"NameCheap API use_sandbox. Set the use_sandbox value in the configuration or use the "+
-					"NAMECHEAP_USE_SANDBOX environment variable. If either is already set, ensure the value "+
-					"is not empty.",
+				"Invalid NameCheap use_sandbox",
+				fmt.Sprintf("The provider cannot create the NameCheap API client as the NAMECHEAP_USE_SANDBOX "+
+					"environment variable is %q, which is not a boolean. Use true/false, 1/0, yes/no or on/off.", v),
+			)
+		}
+	}
+
+	var environment string
+	if !config.Environment.IsNull() {
+		environment = config.Environment.ValueString()
+	} else {
+		environment = strings.ToLower(strings.TrimSpace(os.Getenv("NAMECHEAP_ENVIRONMENT")))
+	}
+	switch {
+	case environment != "" && environment != ENVIRONMENT_PRODUCTION && environment != ENVIRONMENT_SANDBOX:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("environment"),
+			"Invalid NameCheap environment",
+			fmt.Sprintf("The provider cannot create the NameCheap API client as the environment is %q, expected "+
+				"%s or %s.", environment, ENVIRONMENT_PRODUCTION, ENVIRONMENT_SANDBOX),
+		)
+	case environment != "" && !sandbox.IsNull() && sandbox.ValueBool() != (environment == ENVIRONMENT_SANDBOX):
+		resp.Diagnostics.AddAttributeError(
+			path.Root("environment"),
+			"Conflicting NameCheap environment",
+			fmt.Sprintf("The provider cannot create the NameCheap API client as the environment is %s but "+
+				"use_sandbox is %t. Remove one of them, or make them agree.", environment, sandbox.ValueBool()),
+		)
+	}
+	useSandbox := environment == ENVIRONMENT_SANDBOX || sandbox.ValueBool()
+
+	var endpoint string
+	if !config.Endpoint.IsNull() {
+		endpoint = config.Endpoint.ValueString()
+	} else {
+		endpoint = os.Getenv("NAMECHEAP_ENDPOINT")
+	}
+	if endpoint != "" {
+		if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Invalid NameCheap endpoint",
+				"The provider cannot create the NameCheap API client as the endpoint value is not an absolute "+
+					"URL (e.g. http://127.0.0.1:8080/xml.response). Set a valid endpoint value in the configuration "+
+					"or use the NAMECHEAP_ENDPOINT environment variable.",
 			)
 		}
 	}
@@ -238,15 +704,35 @@ func (p *namecheapProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	client := namecheap.NewClient(&namecheap.ClientOptions{
-		UserName:   userName,
-		ApiUser:    apiUser,
-		ApiKey:     apiKey,
-		ClientIp:   clientIp,
-		UseSandbox: useSandbox,
-	})
+	newClient := func(userName, apiUser, apiKey string) *sdk.Client {
+		client := sdk.NewClient(&namecheap.ClientOptions{
+			UserName:   userName,
+			ApiUser:    apiUser,
+			ApiKey:     apiKey,
+			ClientIp:   clientIp,
+			UseSandbox: useSandbox,
+		})
+
+		if endpoint != "" {
+			client.BaseURL = endpoint
+		}
+		client.HTTPClient = httpClient
+		return client
+	}
+
+	clients := &namecheapClients{
+		accounts:            map[string]*sdk.Client{},
+		clientIp:            clientIp,
+		clientIpResolverURL: clientIpResolverURL,
+	}
+	if hasDefault {
+		clients.defaultClient = newClient(userName, apiUser, apiKey)
+	}
+	for name, account := range accounts {
+		clients.accounts[name] = newClient(account.UserName.ValueString(), account.ApiUser.ValueString(), account.ApiKey.ValueString())
+	}
 
-	resp.ResourceData = client
+	resp.ResourceData = clients
 }
 
 func (p *namecheapProvider) DataSources(_ context.Context) []func() datasource.DataSource {
@@ -258,3 +744,15 @@ func (p *namecheapProvider) Resources(_ context.Context) []func() resource.Resou
 		NewNamecheapDomainResource,
 	}
 }
+
+// parseBoolSpelling parses the common spellings of booleans in environment
+// variables, case-insensitively.
+func parseBoolSpelling(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, true
+	case "0", "f", "false", "n", "no", "off":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,232 @@
+package namecheap
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate the provider during
+// acceptance testing. Acceptance tests run against a namecheaptest server
+// instead of NameCheap, so they only need TF_ACC to be set.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"st-namecheap": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccProviderConfig returns a provider block pointed at the fake server.
+func testAccProviderConfig(srv *namecheaptest.Server) string {
+	return fmt.Sprintf(`
+provider "st-namecheap" {
+  user_name   = %q
+  api_user    = %q
+  api_key     = %q
+  client_ip   = %q
+  use_sandbox = false
+  endpoint    = %q
+}
+`, namecheaptest.UserName, namecheaptest.ApiUser, namecheaptest.ApiKey, namecheaptest.ClientIp, srv.Endpoint())
+}
+
+func TestAccProviderClientIpAuto(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	var egressIp atomic.Value
+	resolver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, egressIp.Load())
+	}))
+	defer resolver.Close()
+
+	config := fmt.Sprintf(`
+provider "st-namecheap" {
+  user_name              = %q
+  api_user               = %q
+  api_key                = %q
+  client_ip              = "auto"
+  client_ip_resolver_url = %q
+  use_sandbox            = false
+  endpoint               = %q
+}
+
+resource "st-namecheap_domain" "test" {
+  domain      = "example.com"
+  nameservers = ["ns1.example.net", "ns2.example.net"]
+  max_price   = 10
+}
+`, namecheaptest.UserName, namecheaptest.ApiUser, namecheaptest.ApiKey, resolver.URL, srv.Endpoint())
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					egressIp.Store("203.0.113.7")
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile(`client IP 203\.0\.113\.7, which was detected automatically`),
+			},
+			{
+				PreConfig: func() {
+					egressIp.Store(namecheaptest.ClientIp)
+				},
+				Config: config,
+				Check:  resource.TestCheckResourceAttr(testAccDomainResourceName, "domain", "example.com"),
+			},
+		},
+	})
+}
+
+func TestParseBoolSpelling(t *testing.T) {
+	for s, expected := range map[string]bool{"true": true, "YES": true, " on": true, "1": true, "False": false, "no": false, "off": false, "0": false} {
+		if b, ok := parseBoolSpelling(s); !ok || b != expected {
+			t.Errorf("expected %q to parse as %t, got %t, %t", s, expected, b, ok)
+		}
+	}
+	for _, s := range []string{"", "sandbox", "2"} {
+		if _, ok := parseBoolSpelling(s); ok {
+			t.Errorf("expected %q not to parse", s)
+		}
+	}
+}
+
+func TestAccProviderEnvironmentConflict(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "st-namecheap" {
+  user_name   = "xxx"
+  api_user    = "xxx"
+  api_key     = "xxx"
+  client_ip   = "127.0.0.1"
+  environment = "sandbox"
+  use_sandbox = false
+}
+
+resource "st-namecheap_domain" "test" {
+  domain      = "example.com"
+  nameservers = ["ns1.example.net", "ns2.example.net"]
+  max_price   = 10
+}
+`,
+				ExpectError: regexp.MustCompile(`Conflicting NameCheap environment`),
+			},
+		},
+	})
+}
+
+func TestAccProviderCredentialsFileMissingProfile(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(credentialsFile, []byte("[brand]\napi_key = brandkey\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NAMECHEAP_SHARED_CREDENTIALS_FILE", credentialsFile)
+	for _, name := range []string{"NAMECHEAP_PROFILE", "NAMECHEAP_USER_NAME", "NAMECHEAP_API_USER", "NAMECHEAP_API_KEY", "NAMECHEAP_CLIENT_IP"} {
+		t.Setenv(name, "")
+	}
+
+	domainConfig := `
+resource "st-namecheap_domain" "test" {
+  domain      = "example.com"
+  nameservers = ["ns1.example.net", "ns2.example.net"]
+  max_price   = 10
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The [default] profile is not needed with explicit credentials.
+			{
+				Config: testAccProviderConfig(srv) + domainConfig,
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "st-namecheap" {
+  use_sandbox = false
+  endpoint    = %q
+}
+`, srv.Endpoint()) + domainConfig,
+				ExpectError: regexp.MustCompile(`profile not found:\s+\[default\]`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "st-namecheap" {
+  user_name   = %q
+  api_user    = %q
+  api_key     = %q
+  client_ip   = %q
+  profile     = "reseller"
+  use_sandbox = false
+  endpoint    = %q
+}
+`, namecheaptest.UserName, namecheaptest.ApiUser, namecheaptest.ApiKey, namecheaptest.ClientIp, srv.Endpoint()) + domainConfig,
+				ExpectError: regexp.MustCompile(`profile not found:\s+\[reseller\]`),
+			},
+		},
+	})
+}
+
+func TestAccProviderDefaultCredentialsFileUnusable(t *testing.T) {
+	garbage := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(garbage, ".namecheap"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(garbage, ".namecheap", "credentials"), []byte("garbage\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	domainConfig := `
+resource "st-namecheap_domain" "test" {
+  domain      = "example.com"
+  nameservers = ["ns1.example.net", "ns2.example.net"]
+  max_price   = 10
+}
+`
+	for name, home := range map[string]string{"home unset": "", "garbage file": garbage} {
+		t.Run(name, func(t *testing.T) {
+			srv := namecheaptest.NewServer()
+			defer srv.Close()
+			t.Setenv("HOME", home)
+			for _, name := range []string{"NAMECHEAP_PROFILE", "NAMECHEAP_SHARED_CREDENTIALS_FILE", "NAMECHEAP_USER_NAME", "NAMECHEAP_API_USER", "NAMECHEAP_API_KEY", "NAMECHEAP_CLIENT_IP"} {
+				t.Setenv(name, "")
+			}
+
+			steps := []resource.TestStep{
+				// The default file is not needed with explicit credentials.
+				{
+					Config: testAccProviderConfig(srv) + domainConfig,
+				},
+			}
+			if home != "" {
+				steps = append(steps, resource.TestStep{
+					Config: fmt.Sprintf(`
+provider "st-namecheap" {
+  use_sandbox = false
+  endpoint    = %q
+}
+`, srv.Endpoint()) + domainConfig,
+					ExpectError: regexp.MustCompile(`expected key = value`),
+				})
+			}
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps:                    steps,
+			})
+		})
+	}
+}
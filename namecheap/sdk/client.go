@@ -0,0 +1,61 @@
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+)
+
+const (
+	productionURL = "https://api.namecheap.com/xml.response"
+	sandboxURL    = "https://api.sandbox.namecheap.com/xml.response"
+)
+
+// Client sends the commands of the NameCheap XML API. Unlike the
+// go-namecheap-sdk client, the HTTP client sending the requests can be set.
+type Client struct {
+	// ClientOptions are the credentials sent with every command.
+	ClientOptions *namecheap.ClientOptions
+	// BaseURL is the URL the commands are posted to.
+	BaseURL string
+	// HTTPClient sends the requests.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client posting to the production or sandbox API,
+// depending on options.UseSandbox.
+func NewClient(options *namecheap.ClientOptions) *Client {
+	client := &Client{
+		ClientOptions: options,
+		BaseURL:       productionURL,
+		HTTPClient:    cleanhttp.DefaultClient(),
+	}
+	if options.UseSandbox {
+		client.BaseURL = sandboxURL
+	}
+	return client
+}
+
+// newRequest returns the form POST of a command with body as parameters,
+// along with the credentials.
+func (c *Client) newRequest(body map[string]string) (*http.Request, error) {
+	form := url.Values{}
+	for k, v := range body {
+		form.Set(k, v)
+	}
+	form.Set("Username", c.ClientOptions.UserName)
+	form.Set("ApiUser", c.ClientOptions.ApiUser)
+	form.Set("ApiKey", c.ClientOptions.ApiKey)
+	form.Set("ClientIp", c.ClientOptions.ClientIp)
+
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
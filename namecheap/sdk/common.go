@@ -1,20 +1,115 @@
 package sdk
 
 import (
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"net/http"
 
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
-
 	"github.com/cenkalti/backoff/v4"
 )
 
-func doXmlWithBackoff(client *namecheap.Client, body map[string]string, obj interface{}) (*http.Response, error) {
+// ApiResponse is the envelope wrapping the response of every NameCheap XML API
+// command, with T being the command specific content of CommandResponse.
+type ApiResponse[T any] struct {
+	XMLName           xml.Name     `xml:"ApiResponse"`
+	Status            string       `xml:"Status,attr"`
+	Errors            []ApiMessage `xml:"Errors>Error"`
+	Warnings          []ApiMessage `xml:"Warnings>Warning"`
+	RequestedCommand  string       `xml:"RequestedCommand"`
+	CommandResponse   *T           `xml:"CommandResponse"`
+	Server            string       `xml:"Server"`
+	GMTTimeDifference string       `xml:"GMTTimeDifference"`
+	ExecutionTime     float64      `xml:"ExecutionTime"`
+}
+
+// ApiMessage is an error or warning reported in the response envelope.
+type ApiMessage struct {
+	Number  string `xml:"Number,attr"`
+	Message string `xml:",chardata"`
+}
+
+// Error is returned by Do when NameCheap reports errors for a command.
+type Error struct {
+	Command string
+	Errors  []ApiMessage
+}
+
+// Error formats the first reported error as "message (number)".
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Errors[0].Message, e.Errors[0].Number)
+}
+
+// Number returns the number of the first reported error.
+func (e *Error) Number() string {
+	return e.Errors[0].Number
+}
+
+// paidCommands charge the account, so they are not sent again once NameCheap
+// may have received them.
+var paidCommands = map[string]bool{
+	"namecheap.domains.create":     true,
+	"namecheap.domains.renew":      true,
+	"namecheap.domains.reactivate": true,
+}
+
+// ErrMaybeProcessed is returned by Do when the response to a paid command is
+// lost, in which case NameCheap may have processed and charged it.
+var ErrMaybeProcessed = errors.New("NameCheap may have processed the order, check the account before trying again")
+
+// Do sends command with the given parameters and decodes the response
+// envelope, with the content of CommandResponse decoded into T. Errors
+// reported by NameCheap are returned as *Error.
+func Do[T any](client *Client, command string, params map[string]string) (*ApiResponse[T], error) {
+	var response ApiResponse[T]
+
+	body := map[string]string{"Command": command}
+	for k, v := range params {
+		body[k] = v
+	}
+	if _, err := doXmlWithBackoff(client, body, &response, !paidCommands[command]); err != nil {
+		return nil, err
+	}
+
+	if len(response.Errors) > 0 {
+		return nil, &Error{Command: command, Errors: response.Errors}
+	}
+
+	return &response, nil
+}
+
+// doXmlWithBackoff posts body and decodes the XML response into obj. HTTP
+// 405, which NameCheap answers when rate limiting, is retried with
+// exponential backoff. So are failed requests and undecodable responses when
+// retry is set, otherwise they are wrapped in ErrMaybeProcessed.
+func doXmlWithBackoff(client *Client, body map[string]string, obj interface{}, retry bool) (*http.Response, error) {
 	var requestResponse *http.Response
 
+	uncertain := func(err error) error {
+		if retry {
+			return err
+		}
+		return backoff.Permanent(fmt.Errorf("%w: %s", ErrMaybeProcessed, err))
+	}
 	operation := func() error {
-		var err error
-		requestResponse, err = client.DoXML(body, obj)
-		return err
+		req, err := client.newRequest(body)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		resp, err := client.HTTPClient.Do(req)
+		if err != nil {
+			return uncertain(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusMethodNotAllowed {
+			return fmt.Errorf("%s: rate limited by NameCheap", resp.Status)
+		}
+
+		requestResponse = resp
+		if err := xml.NewDecoder(resp.Body).Decode(obj); err != nil {
+			return uncertain(fmt.Errorf("unable to parse server response: %w", err))
+		}
+		return nil
 	}
 	if err := backoff.Retry(operation, backoff.NewExponentialBackOff()); err != nil {
 		return nil, err
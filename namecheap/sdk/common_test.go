@@ -0,0 +1,106 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+// newTestClient returns a client sending its requests to a namecheaptest
+// Server or Recorder.
+func newTestClient(target interface {
+	ClientOptions() *namecheap.ClientOptions
+	Endpoint() string
+}) *Client {
+	client := NewClient(target.ClientOptions())
+	client.BaseURL = target.Endpoint()
+	return client
+}
+
+// replayPaidCommand returns a client replaying the recorded responses of
+// commands charging the account. NameCheap sandbox payment isn't working, so
+// these responses can't be re-recorded and the test is skipped when
+// recording.
+func replayPaidCommand(t *testing.T) *Client {
+	t.Helper()
+	if record, _ := strconv.ParseBool(os.Getenv("NAMECHEAP_RECORD")); record {
+		t.Skip("NameCheap sandbox payment isn't working, keeping the recorded responses")
+	}
+	return newTestClient(namecheaptest.NewRecorder(t, "testdata"))
+}
+
+func TestDo(t *testing.T) {
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
+
+	resp, err := Do[domainsCheckCommandResponse](client, "namecheap.domains.check", map[string]string{
+		"DomainList": "example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "OK" || resp.RequestedCommand != "namecheap.domains.check" || resp.Server != "PHX01SBAPIEXT05" {
+		t.Errorf("unexpected envelope: %+v", resp)
+	}
+	if resp.GMTTimeDifference != "--4:00" || resp.ExecutionTime != 0.383 || len(resp.Warnings) != 0 {
+		t.Errorf("unexpected envelope: %+v", resp)
+	}
+	if resp.CommandResponse.Result.Domain != "example.com" {
+		t.Errorf("unexpected result: %+v", resp.CommandResponse.Result)
+	}
+}
+
+func TestDoError(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	srv.FailNext("namecheap.domains.check", "2011169", "Only 50 domains are allowed in a single check command")
+	_, err := Do[domainsCheckCommandResponse](newTestClient(srv), "namecheap.domains.check", map[string]string{
+		"DomainList": "example.com",
+	})
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if apiErr.Command != "namecheap.domains.check" || apiErr.Number() != "2011169" {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+	if got := apiErr.Error(); got != "Only 50 domains are allowed in a single check command (2011169)" {
+		t.Errorf("unexpected error message: %s", got)
+	}
+}
+
+func TestDoRateLimited(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" || r.FormValue("ApiKey") != "testkey" {
+			t.Errorf("unexpected request: %v", r.Form)
+		}
+		fmt.Fprintf(w, `<ApiResponse Status="OK"><CommandResponse Type="%s" /></ApiResponse>`, r.FormValue("Command"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(&namecheap.ClientOptions{UserName: "testuser", ApiUser: "testuser", ApiKey: "testkey", ClientIp: "127.0.0.1"})
+	client.BaseURL = srv.URL
+
+	resp, err := Do[struct{}](client, "namecheap.domains.check", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "OK" || requests != 2 {
+		t.Errorf("expected the rate limited request to be retried, got %d requests and %+v", requests, resp)
+	}
+}
@@ -1,45 +1,70 @@
 package sdk
 
 import (
-	"encoding/xml"
-	"fmt"
-
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+	"github.com/shopspring/decimal"
 )
 
 type domainsCheckResult struct {
-	Domain    string `xml:"Domain,attr"`
-	Available bool   `xml:"Available,attr"`
-	Price     string `xml:"PremiumRegistrationPrice,attr"`
+	Domain       string          `xml:"Domain,attr"`
+	Available    bool            `xml:"Available,attr"`
+	IsPremium    bool            `xml:"IsPremiumName,attr"`
+	Price        decimal.Decimal `xml:"PremiumRegistrationPrice,attr"`
+	RenewalPrice decimal.Decimal `xml:"PremiumRenewalPrice,attr"`
+	IcannFee     decimal.Decimal `xml:"IcannFee,attr"`
+	EapFee       decimal.Decimal `xml:"EapFee,attr"`
 }
 
-type domainsCheckResponse struct {
-	XMLName *xml.Name `xml:"ApiResponse"`
-	Errors  *[]struct {
-		Message string `xml:",chardata"`
-		Number  string `xml:"Number,attr"`
-	} `xml:"Errors>Error"`
-	CommandResponse *domainsCheckCommandResponse `xml:"CommandResponse"`
+// Premium reports whether the domain is a premium name.
+func (r *domainsCheckResult) Premium() bool {
+	return r.IsPremium || !r.Price.IsZero()
 }
 
-type domainsCheckCommandResponse struct {
-	Result *domainsCheckResult `xml:"DomainCheckResult"`
+// PremiumPrice returns the price of registering a premium domain for years:
+// the registration price for the first year and the renewal price for the
+// others, with the ICANN fee of every year and the EAP fee. It is zero for
+// domains that are not premium.
+func (r *domainsCheckResult) PremiumPrice(years int) Money {
+	if !r.Premium() {
+		return Money{Amount: decimal.Zero, Currency: CurrencyUSD}
+	}
+	renewals := r.RenewalPrice.Add(r.IcannFee).Mul(decimal.NewFromInt(int64(years - 1)))
+	return Money{Amount: r.Price.Add(r.IcannFee).Add(r.EapFee).Add(renewals), Currency: CurrencyUSD}
 }
 
-func DomainsAvailable(client *namecheap.Client, domains string) (*domainsCheckCommandResponse, error) {
-	var resp domainsCheckResponse
+// EarlyAccessFee returns the EAP fee charged on top of the regular
+// registration price while a new TLD is in its early access period.
+func (r *domainsCheckResult) EarlyAccessFee() Money {
+	return Money{Amount: r.EapFee, Currency: CurrencyUSD}
+}
 
-	params := map[string]string{
-		"Command":    "namecheap.domains.check",
-		"DomainList": domains,
+// PremiumRenewalPrice returns the yearly renewal price of a premium domain.
+func (r *domainsCheckResult) PremiumRenewalPrice() Money {
+	return Money{Amount: r.RenewalPrice, Currency: CurrencyUSD}
+}
+
+// CreatePricing returns the premium and early access pricing that has to be
+// confirmed when registering the domain, or nil if there is none.
+func (r *domainsCheckResult) CreatePricing() *DomainsCreatePricing {
+	if !r.Premium() && r.EapFee.IsZero() {
+		return nil
 	}
-	if _, err := doXmlWithBackoff(client, params, &resp); err != nil {
-		return nil, err
+	return &DomainsCreatePricing{
+		IsPremium:    r.Premium(),
+		PremiumPrice: r.Price,
+		EapFee:       r.EapFee,
 	}
+}
+
+type domainsCheckCommandResponse struct {
+	Result *domainsCheckResult `xml:"DomainCheckResult"`
+}
 
-	if resp.Errors != nil && len(*resp.Errors) > 0 {
-		apiErr := (*resp.Errors)[0]
-		return nil, fmt.Errorf("%s (%s)", apiErr.Message, apiErr.Number)
+func DomainsAvailable(client *Client, domains string) (*domainsCheckCommandResponse, error) {
+	resp, err := Do[domainsCheckCommandResponse](client, "namecheap.domains.check", map[string]string{
+		"DomainList": domains,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return resp.CommandResponse, nil
@@ -1,25 +1,36 @@
 package sdk
 
 import (
-	"os"
 	"testing"
 
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+	"github.com/shopspring/decimal"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
 )
 
 func TestDomainsCheck(t *testing.T) {
-	client := namecheap.NewClient(&namecheap.ClientOptions{
-		UserName:   os.Getenv("NAMECHEAP_USER_NAME"),
-		ApiUser:    os.Getenv("NAMECHEAP_API_USER"),
-		ApiKey:     os.Getenv("NAMECHEAP_API_KEY"),
-		ClientIp:   os.Getenv("NAMECHEAP_CLIENT_IP"),
-		UseSandbox: os.Getenv("NAMECHEAP_USE_SANDBOX") == "true",
-	})
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
 
 	r, err := DomainsAvailable(client, "example.com")
 	if err != nil {
-		t.Error(err)
-	} else {
-		t.Log(r.Result.Available)
+		t.Fatal(err)
+	}
+	if r.Result.Domain != "example.com" || !r.Result.Available || !r.Result.Price.IsZero() {
+		t.Errorf("unexpected result: %+v", r.Result)
+	}
+}
+
+func TestDomainsCheckPremiumPrice(t *testing.T) {
+	r := domainsCheckResult{
+		IsPremium:    true,
+		Price:        decimal.NewFromInt(120),
+		RenewalPrice: decimal.RequireFromString("95.5"),
+		IcannFee:     decimal.RequireFromString("0.18"),
+		EapFee:       decimal.NewFromInt(5),
+	}
+	for years, expected := range map[int]string{1: "125.18 USD", 3: "316.54 USD"} {
+		if got := r.PremiumPrice(years).String(); got != expected {
+			t.Errorf("expected %s for %d years, got %s", expected, years, got)
+		}
 	}
 }
@@ -1,12 +1,31 @@
 package sdk
 
 import (
-	"encoding/xml"
 	"fmt"
+	"strings"
 
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+	"github.com/shopspring/decimal"
 )
 
+// DomainsCreatePricing carries the premium and early access prices NameCheap
+// requires to be confirmed when registering such domains.
+type DomainsCreatePricing struct {
+	IsPremium    bool
+	PremiumPrice decimal.Decimal
+	EapFee       decimal.Decimal
+}
+
+// DomainsCreateOptions holds the optional parameters of
+// namecheap.domains.create.
+type DomainsCreateOptions struct {
+	// IdnCode is the language code of an internationalized domain name.
+	IdnCode string
+	// ExtendedAttributes are the registry specific attributes of some TLDs.
+	ExtendedAttributes map[string]string
+	// Pricing confirms the price of premium and early access domains.
+	Pricing *DomainsCreatePricing
+}
+
 type domainsCreateResult struct {
 	Domain        string `xml:"Domain,attr"`
 	Registered    bool   `xml:"Registered,attr"`
@@ -17,20 +36,8 @@ type domainsCreateCommandResponse struct {
 	Result *domainsCreateResult `xml:"DomainCreateResult"`
 }
 
-type domainsCreateResponse struct {
-	XMLName *xml.Name `xml:"ApiResponse"`
-	Errors  *[]struct {
-		Message string `xml:",chardata"`
-		Number  string `xml:"Number,attr"`
-	} `xml:"Errors>Error"`
-	CommandResponse *domainsCreateCommandResponse `xml:"CommandResponse"`
-}
-
-func DomainsCreate(client *namecheap.Client, domainName string, years string, nameservers string, info *UserAddrGetInfoCommandResponse) (*domainsCreateCommandResponse, error) {
-	var response domainsCreateResponse
-
+func DomainsCreate(client *Client, domainName string, years string, nameservers string, info *UserAddrGetInfoCommandResponse, opts *DomainsCreateOptions) (*domainsCreateCommandResponse, error) {
 	params := map[string]string{
-		"Command":    "namecheap.domains.create",
 		"DomainName": domainName,
 
 		"Years":                   years,
@@ -78,18 +85,38 @@ func DomainsCreate(client *namecheap.Client, domainName string, years string, na
 		"AuxBillingPhone":         info.Result.Phone,
 		"AuxBillingEmailAddress":  info.Result.EmailAddress,
 
-		"Extended attributes": "",
-		"Nameservers":         nameservers,
+		"Nameservers": nameservers,
 	}
-	if _, err := doXmlWithBackoff(client, params, &response); err != nil {
-		return nil, err
+	if opts == nil {
+		opts = &DomainsCreateOptions{}
 	}
-
-	if response.Errors != nil && len(*response.Errors) > 0 {
-		apiErr := (*response.Errors)[0]
-		return nil, fmt.Errorf("%s (%s)", apiErr.Message, apiErr.Number)
+	names := make([]string, 0, len(opts.ExtendedAttributes))
+	for name := range opts.ExtendedAttributes {
+		names = append(names, name)
+	}
+	if colliding := CollidingExtendedAttributes(names); len(colliding) > 0 {
+		return nil, fmt.Errorf("extended attributes [%s] are parameters of namecheap.domains.create", strings.Join(colliding, ", "))
+	}
+	for name, value := range opts.ExtendedAttributes {
+		params[name] = value
+	}
+	if opts.IdnCode != "" {
+		params["IdnCode"] = opts.IdnCode
+	}
+	if pricing := opts.Pricing; pricing != nil {
+		if pricing.IsPremium {
+			params["IsPremiumDomain"] = "true"
+			params["PremiumPrice"] = pricing.PremiumPrice.String()
+		}
+		if !pricing.EapFee.IsZero() {
+			params["EapFee"] = pricing.EapFee.String()
+		}
 	}
 
-	return response.CommandResponse, nil
+	resp, err := Do[domainsCreateCommandResponse](client, "namecheap.domains.create", params)
+	if err != nil {
+		return nil, err
+	}
 
+	return resp.CommandResponse, nil
 }
@@ -1,18 +1,45 @@
 package sdk
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
 )
 
 func TestDomainsCreate(t *testing.T) {
-	// Because NameCheap sandbox payment isn't working, cannot process domain
-	// creation.
-
-	// _ = namecheap.NewClient(&namecheap.ClientOptions{
-	// 	UserName:   os.Getenv("NAMECHEAP_USER_NAME"),
-	// 	ApiUser:    os.Getenv("NAMECHEAP_API_USER"),
-	// 	ApiKey:     os.Getenv("NAMECHEAP_API_KEY"),
-	// 	ClientIp:   os.Getenv("NAMECHEAP_CLIENT_IP"),
-	// 	UseSandbox: os.Getenv("NAMECHEAP_USE_SANDBOX") == "true",
-	// })
+	client := replayPaidCommand(t)
+
+	info, err := UserAddrGetInfo(client, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := DomainsCreate(client, "example.com", "1", "ns1.example.net,ns2.example.net", info, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Result.Domain != "example.com" || !r.Result.Registered || r.Result.ChargedAmount != "10.8700" {
+		t.Errorf("unexpected result: %+v", r.Result)
+	}
+}
+
+func TestDomainsCreateCollidingExtendedAttributes(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	info, err := UserAddrGetInfo(newTestClient(srv), "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = DomainsCreate(newTestClient(srv), "example.com", "1", "ns1.example.net,ns2.example.net", info, &DomainsCreateOptions{
+		ExtendedAttributes: map[string]string{"Years": "10", "RegistrantEmailAddress": "jane@example.org"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "[RegistrantEmailAddress, Years]") {
+		t.Fatalf("expected colliding extended attributes error, got %v", err)
+	}
+	if _, ok := srv.Domain("example.com"); ok {
+		t.Error("expected the domain not to be registered")
+	}
 }
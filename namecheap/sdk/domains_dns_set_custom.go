@@ -0,0 +1,29 @@
+package sdk
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+)
+
+func DomainsDNSSetCustom(client *Client, domain string, nameservers []string) (*namecheap.DomainsDNSSetCustomCommandResponse, error) {
+	if len(nameservers) < 2 {
+		return nil, errors.New("invalid nameservers: must contain minimum two items")
+	}
+	parsedDomain, err := namecheap.ParseDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := Do[namecheap.DomainsDNSSetCustomCommandResponse](client, "namecheap.domains.dns.setCustom", map[string]string{
+		"SLD":         parsedDomain.SLD,
+		"TLD":         parsedDomain.TLD,
+		"Nameservers": strings.Join(nameservers, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.CommandResponse, nil
+}
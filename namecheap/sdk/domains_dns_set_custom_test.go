@@ -0,0 +1,46 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+func TestDomainsDNSSetCustom(t *testing.T) {
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
+
+	r, err := DomainsDNSSetCustom(client, "example.com", []string{"ns1.example.net", "ns2.example.net"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *r.DomainDNSSetCustomResult.Domain != "example.com" || !*r.DomainDNSSetCustomResult.Updated {
+		t.Errorf("unexpected result: %+v", r.DomainDNSSetCustomResult)
+	}
+}
+
+func TestDomainsDNSSetCustomRequest(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.AddDomain(namecheaptest.Domain{Name: "example.co.uk", Created: time.Now(), Expires: time.Now().AddDate(1, 0, 0)})
+	client := newTestClient(srv)
+
+	if _, err := DomainsDNSSetCustom(client, "example.co.uk", []string{"ns1.example.net", "ns2.example.net"}); err != nil {
+		t.Fatal(err)
+	}
+	req := srv.Requests()[0]
+	if req.Get("Command") != "namecheap.domains.dns.setCustom" || req.Get("SLD") != "example" || req.Get("TLD") != "co.uk" ||
+		req.Get("Nameservers") != "ns1.example.net,ns2.example.net" {
+		t.Errorf("unexpected request: %v", req)
+	}
+
+	if _, err := DomainsDNSSetCustom(client, "example.co.uk", []string{"ns1.example.net"}); err == nil {
+		t.Error("expected a single nameserver to be rejected")
+	}
+	if _, err := DomainsDNSSetCustom(client, "example", []string{"ns1.example.net", "ns2.example.net"}); err == nil {
+		t.Error("expected an invalid domain to be rejected")
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("expected invalid arguments not to be sent, got %d requests", n)
+	}
+}
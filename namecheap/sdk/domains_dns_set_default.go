@@ -0,0 +1,22 @@
+package sdk
+
+import (
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+)
+
+func DomainsDNSSetDefault(client *Client, domain string) (*namecheap.DomainsDNSSetDefaultCommandResponse, error) {
+	parsedDomain, err := namecheap.ParseDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := Do[namecheap.DomainsDNSSetDefaultCommandResponse](client, "namecheap.domains.dns.setDefault", map[string]string{
+		"SLD": parsedDomain.SLD,
+		"TLD": parsedDomain.TLD,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.CommandResponse, nil
+}
@@ -0,0 +1,42 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+func TestDomainsDNSSetDefault(t *testing.T) {
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
+
+	r, err := DomainsDNSSetDefault(client, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *r.DomainDNSSetDefaultResult.Domain != "example.com" || !*r.DomainDNSSetDefaultResult.Updated {
+		t.Errorf("unexpected result: %+v", r.DomainDNSSetDefaultResult)
+	}
+}
+
+func TestDomainsDNSSetDefaultRequest(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.AddDomain(namecheaptest.Domain{Name: "example.co.uk", Created: time.Now(), Expires: time.Now().AddDate(1, 0, 0)})
+	client := newTestClient(srv)
+
+	if _, err := DomainsDNSSetDefault(client, "example.co.uk"); err != nil {
+		t.Fatal(err)
+	}
+	req := srv.Requests()[0]
+	if req.Get("Command") != "namecheap.domains.dns.setDefault" || req.Get("SLD") != "example" || req.Get("TLD") != "co.uk" {
+		t.Errorf("unexpected request: %v", req)
+	}
+
+	if _, err := DomainsDNSSetDefault(client, "example"); err == nil {
+		t.Error("expected an invalid domain to be rejected")
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("expected an invalid domain not to be sent, got %d requests", n)
+	}
+}
@@ -1,11 +1,7 @@
 package sdk
 
 import (
-	"encoding/xml"
 	"errors"
-	"fmt"
-
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
 )
 
 var cache *domainsGetContactsCommandResponse
@@ -29,7 +25,7 @@ type contact struct {
 }
 
 type domainsContactsResult struct {
-	Domain     string  `xml:"Domain,attr"`
+	Domain     string   `xml:"Domain,attr"`
 	Registrant *contact `xml:"Registrant"`
 	Tech       *contact `xml:"Tech"`
 	Admin      *contact `xml:"Admin"`
@@ -40,18 +36,9 @@ type domainsGetContactsCommandResponse struct {
 	Result *domainsContactsResult `xml:"DomainContactsResult"`
 }
 
-type domainsGetContactsResponse struct {
-	XMLName *xml.Name `xml:"ApiResponse"`
-	Errors  *[]struct {
-		Message string `xml:",chardata"`
-		Number  string `xml:"Number,attr"`
-	} `xml:"Errors>Error"`
-	CommandResponse *domainsGetContactsCommandResponse `xml:"CommandResponse"`
-}
-
-func DomainsGetContacts(client *namecheap.Client) (*domainsGetContactsCommandResponse, error) {
+func DomainsGetContacts(client *Client) (*domainsGetContactsCommandResponse, error) {
 	if cache == nil {
-		r, err := client.Domains.GetList(&namecheap.DomainsGetListArgs{})
+		r, err := DomainsGetList(client, "")
 		if err != nil {
 			return nil, err
 		}
@@ -60,22 +47,14 @@ func DomainsGetContacts(client *namecheap.Client) (*domainsGetContactsCommandRes
 		}
 		domain := (*r.Domains)[0]
 
-		var response domainsGetContactsResponse
-
-		params := map[string]string{
-			"Command":    "namecheap.domains.getContacts",
+		resp, err := Do[domainsGetContactsCommandResponse](client, "namecheap.domains.getContacts", map[string]string{
 			"DomainName": *domain.Name,
-		}
-		if _, err = doXmlWithBackoff(client, params, &response); err != nil {
+		})
+		if err != nil {
 			return nil, err
 		}
 
-		if response.Errors != nil && len(*response.Errors) > 0 {
-			apiErr := (*response.Errors)[0]
-			return nil, fmt.Errorf("%s (%s)", apiErr.Message, apiErr.Number)
-		}
-
-		cache = response.CommandResponse
+		cache = resp.CommandResponse
 	}
 
 	return cache, nil
@@ -1,25 +1,19 @@
 package sdk
 
 import (
-	"os"
 	"testing"
 
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
 )
 
 func TestDomainsGetContacts(t *testing.T) {
-	client := namecheap.NewClient(&namecheap.ClientOptions{
-		UserName:   os.Getenv("NAMECHEAP_USER_NAME"),
-		ApiUser:    os.Getenv("NAMECHEAP_API_USER"),
-		ApiKey:     os.Getenv("NAMECHEAP_API_KEY"),
-		ClientIp:   os.Getenv("NAMECHEAP_CLIENT_IP"),
-		UseSandbox: os.Getenv("NAMECHEAP_USE_SANDBOX") == "true",
-	})
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
 
-	if _, err := DomainsGetContacts(client); err != nil {
-		// "no purchased domains" error is expected.
-		if err.Error() != "no purchased domains" {
-			t.Error(err)
-		}
+	r, err := DomainsGetContacts(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Result.Domain != "example.com" || r.Result.Registrant == nil || r.Result.Registrant.EmailAddress != "john@example.com" {
+		t.Errorf("unexpected result: %+v", r.Result)
 	}
 }
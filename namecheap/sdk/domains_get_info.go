@@ -0,0 +1,17 @@
+package sdk
+
+import (
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+)
+
+func DomainsGetInfo(client *Client, domain string) (*namecheap.DomainsGetInfoCommandResponse, error) {
+	resp, err := Do[namecheap.DomainsGetInfoCommandResponse](client, "namecheap.domains.getInfo", map[string]string{
+		"DomainName": domain,
+		"HostName":   domain,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.CommandResponse, nil
+}
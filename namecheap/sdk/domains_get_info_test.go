@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+func TestDomainsGetInfo(t *testing.T) {
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
+
+	r, err := DomainsGetInfo(client, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := r.DomainDNSGetListResult
+	if *info.DomainName != "example.com" || *info.IsPremium || *info.PremiumDnsSubscription.IsActive {
+		t.Errorf("unexpected result: %+v", info)
+	}
+	if *info.DnsDetails.ProviderType != "CUSTOM" || len(*info.DnsDetails.Nameservers) != 2 || (*info.DnsDetails.Nameservers)[1] != "ns2.example.net" {
+		t.Errorf("unexpected DNS details: %+v", info.DnsDetails)
+	}
+}
+
+func TestDomainsGetInfoRequest(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.AddDomain(namecheaptest.Domain{Name: "example.com", Created: time.Now(), Expires: time.Now().AddDate(1, 0, 0)})
+
+	if _, err := DomainsGetInfo(newTestClient(srv), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	req := srv.Requests()[0]
+	if req.Get("Command") != "namecheap.domains.getInfo" || req.Get("DomainName") != "example.com" || req.Get("HostName") != "example.com" {
+		t.Errorf("unexpected request: %v", req)
+	}
+}
@@ -0,0 +1,21 @@
+package sdk
+
+import (
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+)
+
+// DomainsGetList returns the first page of the domains of the account,
+// containing searchTerm unless it is empty.
+func DomainsGetList(client *Client, searchTerm string) (*namecheap.DomainsGetListCommandResponse, error) {
+	params := map[string]string{}
+	if searchTerm != "" {
+		params["SearchTerm"] = searchTerm
+	}
+
+	resp, err := Do[namecheap.DomainsGetListCommandResponse](client, "namecheap.domains.getList", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.CommandResponse, nil
+}
@@ -0,0 +1,19 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+func TestDomainsGetList(t *testing.T) {
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
+
+	r, err := DomainsGetList(client, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Domains == nil || len(*r.Domains) != 1 || *(*r.Domains)[0].Name != "example.com" || *(*r.Domains)[0].AutoRenew {
+		t.Errorf("unexpected domains: %+v", r.Domains)
+	}
+}
@@ -1,22 +1,15 @@
 package sdk
 
-import (
-	"testing"
-)
+import "testing"
 
 func TestDomainsReactivate(t *testing.T) {
-	// Because NameCheap sandbox payment isn't working, cannot process domain
-	// reactivation.
-
-	// client := namecheap.NewClient(&namecheap.ClientOptions{
-	// 	UserName:   os.Getenv("NAMECHEAP_USER_NAME"),
-	// 	ApiUser:    os.Getenv("NAMECHEAP_API_USER"),
-	// 	ApiKey:     os.Getenv("NAMECHEAP_API_KEY"),
-	// 	ClientIp:   os.Getenv("NAMECHEAP_CLIENT_IP"),
-	// 	UseSandbox: os.Getenv("NAMECHEAP_USE_SANDBOX") == "true",
-	// })
-
-	// if _, err := DomainsReactivate(client, "example.com", "2"); err != nil {
-	// 	t.Error(err)
-	// }
+	client := replayPaidCommand(t)
+
+	r, err := DomainsReactivate(client, "example.com", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Result.Domain != "example.com" || !r.Result.IsSuccess {
+		t.Errorf("unexpected result: %+v", r.Result)
+	}
 }
@@ -1,22 +1,49 @@
 package sdk
 
 import (
+	"errors"
+	"math"
+	"net/http"
 	"testing"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
 )
 
 func TestDomainsRenew(t *testing.T) {
-	// Because NameCheap sandbox payment isn't working, cannot process domain
-	// renewal.
+	client := replayPaidCommand(t)
+
+	r, err := DomainsRenew(client, "example.com", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Result.DomainName != "example.com" || !r.Result.Renew {
+		t.Errorf("unexpected result: %+v", r.Result)
+	}
+}
+
+func TestDomainsRenewTimeout(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.AddDomain(namecheaptest.Domain{Name: "example.com", Created: time.Now(), Expires: time.Now().AddDate(0, 1, 0)})
+	srv.Delay("namecheap.domains.renew", 500*time.Millisecond)
 
-	// client := namecheap.NewClient(&namecheap.ClientOptions{
-	// 	UserName:   os.Getenv("NAMECHEAP_USER_NAME"),
-	// 	ApiUser:    os.Getenv("NAMECHEAP_API_USER"),
-	// 	ApiKey:     os.Getenv("NAMECHEAP_API_KEY"),
-	// 	ClientIp:   os.Getenv("NAMECHEAP_CLIENT_IP"),
-	// 	UseSandbox: os.Getenv("NAMECHEAP_USE_SANDBOX") == "true",
-	// })
+	client := newTestClient(srv)
+	client.HTTPClient = &http.Client{Timeout: 100 * time.Millisecond}
 
-	// if _, err := DomainsRenew(client, "example.com", "1"); err != nil {
-	// 	t.Error(err)
-	// }
+	if _, err := DomainsRenew(client, "example.com", "1"); !errors.Is(err, ErrMaybeProcessed) {
+		t.Fatalf("expected the renewal to be reported as maybe processed, got %v", err)
+	}
+	renewals := 0
+	for _, command := range srv.Commands() {
+		if command == "namecheap.domains.renew" {
+			renewals++
+		}
+	}
+	if renewals != 1 {
+		t.Errorf("expected the renewal to be sent once, got %d times", renewals)
+	}
+	if got := srv.Balance(); math.Abs(got-(1000-13.16)) > 0.001 {
+		t.Errorf("expected the renewal to be charged once, got a balance of %f", got)
+	}
 }
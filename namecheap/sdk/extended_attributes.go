@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"sort"
+	"strings"
+)
+
+// requiredExtendedAttributes lists the extended attributes NameCheap requires
+// when registering a domain under these TLDs. See
+// https://www.namecheap.com/support/api/extended-attributes/.
+var requiredExtendedAttributes = map[string][]string{
+	"us":     {"RegistrantNexus", "RegistrantPurpose"},
+	"eu":     {"EUAgreeWhoisPolicy", "EUAgreeDeletePolicy", "EUAdrLang"},
+	"ca":     {"CIRALegalType", "CIRAWhoisDisplay", "CIRALanguage", "CIRAAgreementVersion", "CIRAAgreementValue"},
+	"uk":     {"COUKLegalType", "COUKRegisteredfor"},
+	"co.uk":  {"COUKLegalType", "COUKRegisteredfor"},
+	"me.uk":  {"COUKLegalType", "COUKRegisteredfor"},
+	"org.uk": {"COUKLegalType", "COUKRegisteredfor"},
+	"com.au": {"AU_RegistrantIdNumber", "AU_RegistrantIdType"},
+	"net.au": {"AU_RegistrantIdNumber", "AU_RegistrantIdType"},
+	"org.au": {"AU_RegistrantIdNumber", "AU_RegistrantIdType"},
+}
+
+// domainsCreateParams are the lower-cased names of the parameters of
+// namecheap.domains.create set by DomainsCreate or the client, which extended
+// attributes must not override.
+var domainsCreateParams = func() map[string]bool {
+	params := map[string]bool{}
+	for _, name := range []string{
+		"ApiUser", "ApiKey", "UserName", "ClientIp", "Command",
+		"DomainName", "Years", "Nameservers", "IdnCode", "IsPremiumDomain", "PremiumPrice", "EapFee",
+		"PromotionCode", "AddFreeWhoisguard", "WGEnabled",
+	} {
+		params[strings.ToLower(name)] = true
+	}
+	for _, contact := range []string{"Registrant", "Tech", "Admin", "AuxBilling", "Billing"} {
+		for _, field := range []string{
+			"OrganizationName", "JobTitle", "FirstName", "LastName", "Address1", "Address2", "City",
+			"StateProvince", "StateProvinceChoice", "PostalCode", "Country", "Phone", "PhoneExt", "Fax",
+			"EmailAddress",
+		} {
+			params[strings.ToLower(contact+field)] = true
+		}
+	}
+	return params
+}()
+
+// CollidingExtendedAttributes returns the sorted names of attributes that
+// are core parameters of namecheap.domains.create, such as Years or
+// RegistrantEmailAddress. Like NameCheap, it compares the names regardless of
+// case.
+func CollidingExtendedAttributes(names []string) []string {
+	var colliding []string
+	for _, name := range names {
+		if domainsCreateParams[strings.ToLower(name)] {
+			colliding = append(colliding, name)
+		}
+	}
+	sort.Strings(colliding)
+	return colliding
+}
+
+// RequiredExtendedAttributes returns the extended attributes that NameCheap
+// requires to register domain, taking the longest matching TLD. It returns
+// nil for TLDs without known requirements.
+func RequiredExtendedAttributes(domain string) []string {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(domain, ".")), ".")
+	for i := 1; i < len(labels); i++ {
+		if attrs, ok := requiredExtendedAttributes[strings.Join(labels[i:], ".")]; ok {
+			return attrs
+		}
+	}
+	return nil
+}
+
+// MissingExtendedAttributes returns the sorted names of the attributes
+// required for domain that are absent or empty in attrs.
+func MissingExtendedAttributes(domain string, attrs map[string]string) []string {
+	var missing []string
+	for _, name := range RequiredExtendedAttributes(domain) {
+		if strings.TrimSpace(attrs[name]) == "" {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,32 @@
+package sdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingExtendedAttributes(t *testing.T) {
+	cases := []struct {
+		domain string
+		attrs  map[string]string
+		want   []string
+	}{
+		{"example.com", nil, nil},
+		{"example.us", nil, []string{"RegistrantNexus", "RegistrantPurpose"}},
+		{"example.us", map[string]string{"RegistrantNexus": "C11", "RegistrantPurpose": " "}, []string{"RegistrantPurpose"}},
+		{"example.us", map[string]string{"RegistrantNexus": "C11", "RegistrantPurpose": "P1"}, nil},
+		{"Example.CO.UK.", map[string]string{"COUKLegalType": "IND"}, []string{"COUKRegisteredfor"}},
+	}
+	for _, c := range cases {
+		if got := MissingExtendedAttributes(c.domain, c.attrs); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("MissingExtendedAttributes(%q, %v) = %v, want %v", c.domain, c.attrs, got, c.want)
+		}
+	}
+}
+
+func TestCollidingExtendedAttributes(t *testing.T) {
+	got := CollidingExtendedAttributes([]string{"RegistrantNexus", "years", "RegistrantPurpose", "RegistrantEmailAddress"})
+	if want := []string{"RegistrantEmailAddress", "years"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CollidingExtendedAttributes() = %v, want %v", got, want)
+	}
+}
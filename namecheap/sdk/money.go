@@ -0,0 +1,39 @@
+package sdk
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// CurrencyUSD is the currency of amounts NameCheap reports without an
+// explicit currency, such as the fees returned by namecheap.domains.check.
+const CurrencyUSD = "USD"
+
+// Money is an exact amount of money in a currency.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// Add returns the sum of m and o, which must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("cannot add %s to %s", o, m)
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.Currency}, nil
+}
+
+// Cmp compares m and o, which must be in the same currency, and returns -1,
+// 0 or +1 like decimal.Decimal.Cmp.
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, fmt.Errorf("cannot compare %s with %s", m, o)
+	}
+	return m.Amount.Cmp(o.Amount), nil
+}
+
+// String formats m as e.g. "10.87 USD".
+func (m Money) String() string {
+	return m.Amount.StringFixed(2) + " " + m.Currency
+}
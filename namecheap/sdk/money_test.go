@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMoney(t *testing.T) {
+	price := Money{Amount: decimal.RequireFromString("10.28"), Currency: CurrencyUSD}
+	fee := Money{Amount: decimal.RequireFromString("0.18"), Currency: CurrencyUSD}
+
+	total, err := price.Add(fee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total.String() != "10.46 USD" {
+		t.Errorf("unexpected total: %s", total)
+	}
+
+	// 10.46 is not exactly representable as a float, but must still compare
+	// equal to a max price of 10.46.
+	if cmp, err := total.Cmp(Money{Amount: decimal.NewFromFloat(10.46), Currency: CurrencyUSD}); err != nil || cmp != 0 {
+		t.Errorf("expected 10.46 USD to equal max price, got %d (%v)", cmp, err)
+	}
+	if cmp, _ := total.Cmp(Money{Amount: decimal.NewFromFloat(10.45), Currency: CurrencyUSD}); cmp != 1 {
+		t.Errorf("expected 10.46 USD to exceed 10.45 USD")
+	}
+
+	if _, err := total.Cmp(Money{Amount: decimal.NewFromFloat(10.46), Currency: "EUR"}); err == nil {
+		t.Error("expected comparing different currencies to fail")
+	}
+}
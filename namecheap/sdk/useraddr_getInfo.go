@@ -1,11 +1,5 @@
 package sdk
 
-import (
-	"encoding/xml"
-	"fmt"
-
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
-)
 type userAddrGetInfoResult struct {
 	Organization        string `xml:"Organization"`
 	JobTitle            string `xml:"JobTitle"`
@@ -28,30 +22,13 @@ type UserAddrGetInfoCommandResponse struct {
 	Result *userAddrGetInfoResult `xml:"GetAddressInfoResult"`
 }
 
-type userAddrGetInfoResponse struct {
-	XMLName *xml.Name `xml:"ApiResponse"`
-	Errors  *[]struct {
-		Message string `xml:",chardata"`
-		Number  string `xml:"Number,attr"`
-	} `xml:"Errors>Error"`
-	CommandResponse *UserAddrGetInfoCommandResponse `xml:"CommandResponse"`
-}
-
-func UserAddrGetInfo(client *namecheap.Client, addrId string) (*UserAddrGetInfoCommandResponse, error) {
-	var response userAddrGetInfoResponse
-
-	params := map[string]string{
-		"Command":   "namecheap.users.address.getInfo",
+func UserAddrGetInfo(client *Client, addrId string) (*UserAddrGetInfoCommandResponse, error) {
+	resp, err := Do[UserAddrGetInfoCommandResponse](client, "namecheap.users.address.getInfo", map[string]string{
 		"AddressId": addrId,
-	}
-	if _, err := doXmlWithBackoff(client, params, &response); err != nil {
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if response.Errors != nil && len(*response.Errors) > 0 {
-		apiErr := (*response.Errors)[0]
-		return nil, fmt.Errorf("%s (%s)", apiErr.Message, apiErr.Number)
-	}
-
-	return response.CommandResponse, nil
+	return resp.CommandResponse, nil
 }
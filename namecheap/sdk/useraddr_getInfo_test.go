@@ -1,22 +1,19 @@
 package sdk
 
 import (
-	"os"
 	"testing"
 
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
 )
 
 func TestUseraddrGetInfo(t *testing.T) {
-	client := namecheap.NewClient(&namecheap.ClientOptions{
-		UserName:   os.Getenv("NAMECHEAP_USER_NAME"),
-		ApiUser:    os.Getenv("NAMECHEAP_API_USER"),
-		ApiKey:     os.Getenv("NAMECHEAP_API_KEY"),
-		ClientIp:   os.Getenv("NAMECHEAP_CLIENT_IP"),
-		UseSandbox: os.Getenv("NAMECHEAP_USE_SANDBOX") == "true",
-	})
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
 
-	if _, err := UserAddrGetInfo(client, "0"); err != nil {
-		t.Error(err)
+	r, err := UserAddrGetInfo(client, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Result.FirstName != "John" || r.Result.PostalCode != "85284" || r.Result.EmailAddress != "john@example.com" {
+		t.Errorf("unexpected result: %+v", r.Result)
 	}
 }
@@ -1,22 +1,19 @@
 package sdk
 
 import (
-	"os"
 	"testing"
 
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
 )
 
 func TestUseraddrGetList(t *testing.T) {
-	client := namecheap.NewClient(&namecheap.ClientOptions{
-		UserName:   os.Getenv("NAMECHEAP_USER_NAME"),
-		ApiUser:    os.Getenv("NAMECHEAP_API_USER"),
-		ApiKey:     os.Getenv("NAMECHEAP_API_KEY"),
-		ClientIp:   os.Getenv("NAMECHEAP_CLIENT_IP"),
-		UseSandbox: os.Getenv("NAMECHEAP_USE_SANDBOX") == "true",
-	})
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
 
-	if _, err := UserAddrGetList(client); err != nil {
-		t.Error(err)
+	r, err := UserAddrGetList(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Result.List == nil || len(*r.Result.List) != 1 || (*r.Result.List)[0].AddressId != "1218" {
+		t.Errorf("unexpected result: %+v", r.Result)
 	}
 }
@@ -1,17 +1,51 @@
 package sdk
 
 import (
-	"encoding/xml"
 	"fmt"
+	"strings"
 
 	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+	"github.com/shopspring/decimal"
 )
 
+// priceTypeMultiple marks an amount charged once per year of the duration,
+// as opposed to "ABSOLUTE" amounts charged once for the whole duration.
+const priceTypeMultiple = "MULTIPLE"
+
+type price struct {
+	Duration           string          `xml:"Duration,attr"`
+	Price              decimal.Decimal `xml:"YourPrice,attr"`
+	PriceType          string          `xml:"YourPriceType,attr"`
+	AdditionalCost     decimal.Decimal `xml:"YourAdditonalCost,attr"`
+	AdditionalCostType string          `xml:"YourAdditonalCostType,attr"`
+	Currency           string          `xml:"Currency,attr"`
+}
+
+// Total returns the price of the whole duration including additional costs
+// such as the ICANN fee.
+func (p *price) Total() (Money, error) {
+	years, err := decimal.NewFromString(p.Duration)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid price duration %q", p.Duration)
+	}
+
+	amount, additionalCost := p.Price, p.AdditionalCost
+	if strings.EqualFold(p.PriceType, priceTypeMultiple) {
+		amount = amount.Mul(years)
+	}
+	if strings.EqualFold(p.AdditionalCostType, priceTypeMultiple) {
+		additionalCost = additionalCost.Mul(years)
+	}
+
+	currency := p.Currency
+	if currency == "" {
+		currency = CurrencyUSD
+	}
+	return Money{Amount: amount.Add(additionalCost), Currency: currency}, nil
+}
+
 type register struct {
-	Price []*struct {
-		Duration string `xml:"Duration,attr"`
-		Price    string `xml:"YourPrice,attr"`
-	} `xml:"Price"`
+	Price []*price `xml:"Price"`
 }
 
 type userGetPricingResult struct {
@@ -22,36 +56,20 @@ type userGetPricingCommandResponse struct {
 	Result *userGetPricingResult `xml:"UserGetPricingResult"`
 }
 
-type userGetPricingResponse struct {
-	XMLName *xml.Name `xml:"ApiResponse"`
-	Errors  *[]struct {
-		Message string `xml:",chardata"`
-		Number  string `xml:"Number,attr"`
-	} `xml:"Errors>Error"`
-	CommandResponse *userGetPricingCommandResponse `xml:"CommandResponse"`
-}
-
-func UserGetPricing(client *namecheap.Client, action string, domain string) (*userGetPricingCommandResponse, error) {
-	var response userGetPricingResponse
+func UserGetPricing(client *Client, action string, domain string) (*userGetPricingCommandResponse, error) {
 	parsedDomain, err := namecheap.ParseDomain(domain)
 	if err != nil {
 		return nil, err
 	}
 
-	params := map[string]string{
-		"Command": "namecheap.users.getPricing",
+	resp, err := Do[userGetPricingCommandResponse](client, "namecheap.users.getPricing", map[string]string{
 		"ProductType": "DOMAIN",
-		"ActionName" : action,
+		"ActionName":  action,
 		"ProductName": parsedDomain.TLD,
-	}
-	if _, err := doXmlWithBackoff(client, params, &response); err != nil {
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if response.Errors != nil && len(*response.Errors) > 0 {
-		apiErr := (*response.Errors)[0]
-		return nil, fmt.Errorf("%s (%s)", apiErr.Message, apiErr.Number)
-	}
-
-	return response.CommandResponse, nil
+	return resp.CommandResponse, nil
 }
@@ -1,27 +1,51 @@
 package sdk_test
 
 import (
-	"fmt"
-	"os"
 	"testing"
 
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
 	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
-	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
 )
 
 func TestUserGetPricing(t *testing.T) {
-	client := namecheap.NewClient(&namecheap.ClientOptions{
-		UserName:   os.Getenv("NAMECHEAP_USER_NAME"),
-		ApiUser:    os.Getenv("NAMECHEAP_API_USER"),
-		ApiKey:     os.Getenv("NAMECHEAP_API_KEY"),
-		ClientIp:   os.Getenv("NAMECHEAP_CLIENT_IP"),
-		UseSandbox: os.Getenv("NAMECHEAP_USE_SANDBOX") == "true",
-	})
+	recorder := namecheaptest.NewRecorder(t, "testdata")
+	client := sdk.NewClient(recorder.ClientOptions())
+	client.BaseURL = recorder.Endpoint()
 
-	resp, err := sdk.UserGetPricing(client, "register", "COM")
+	resp, err := sdk.UserGetPricing(client, "register", "example.com")
 	if err != nil {
-		t.Error(err)
-	}else {
-		fmt.Printf("%s", resp.Result.ProductCategory.Price[0].Price)
+		t.Fatal(err)
 	}
+	prices := resp.Result.ProductCategory.Price
+	if len(prices) != 3 || prices[0].Duration != "1" || prices[0].Price.String() != "10.28" {
+		t.Errorf("unexpected prices: %+v", prices)
+	}
+	for i, expected := range []string{"10.46 USD", "14.94 USD"} {
+		if got, err := prices[i].Total(); err != nil || got.String() != expected {
+			t.Errorf("expected total price of %s for %s years, got %s (%v)", expected, prices[i].Duration, got, err)
+		}
+	}
+}
+
+func TestUserGetPricingMultiple(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+	srv.SetPrice("register", "com", 3, 10)
+	client := sdk.NewClient(srv.ClientOptions())
+	client.BaseURL = srv.Endpoint()
+
+	resp, err := sdk.UserGetPricing(client, "register", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range resp.Result.ProductCategory.Price {
+		if p.Duration != "3" {
+			continue
+		}
+		if got, err := p.Total(); err != nil || got.String() != "30.54 USD" {
+			t.Errorf("expected yearly price and ICANN fee charged for 3 years, got %s (%v)", got, err)
+		}
+		return
+	}
+	t.Error("no price for 3 years")
 }
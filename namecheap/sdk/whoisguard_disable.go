@@ -0,0 +1,21 @@
+package sdk
+
+type whoisguardDisableResult struct {
+	DomainName string `xml:"DomainName,attr"`
+	IsSuccess  bool   `xml:"IsSuccess,attr"`
+}
+
+type whoisguardDisableCommandResponse struct {
+	Result *whoisguardDisableResult `xml:"WhoisguardDisableResult"`
+}
+
+func WhoisguardDisable(client *Client, whoisguardID string) (*whoisguardDisableCommandResponse, error) {
+	resp, err := Do[whoisguardDisableCommandResponse](client, "namecheap.whoisguard.disable", map[string]string{
+		"WhoisguardID": whoisguardID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.CommandResponse, nil
+}
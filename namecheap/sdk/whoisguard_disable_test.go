@@ -0,0 +1,19 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+func TestWhoisguardDisable(t *testing.T) {
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
+
+	r, err := WhoisguardDisable(client, "53536")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Result.DomainName != "example.com" || !r.Result.IsSuccess {
+		t.Errorf("unexpected result: %+v", r.Result)
+	}
+}
@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Whoisguard is the privacy protection allotted to a domain.
+type Whoisguard struct {
+	ID         string `xml:"ID,attr"`
+	DomainName string `xml:"DomainName,attr"`
+	Status     string `xml:"Status,attr"`
+}
+
+// Enabled reports whether the privacy protection is active.
+func (w *Whoisguard) Enabled() bool {
+	return strings.EqualFold(w.Status, "enabled")
+}
+
+type whoisguardGetListCommandResponse struct {
+	Result *struct {
+		Whoisguards []Whoisguard `xml:"Whoisguard"`
+	} `xml:"WhoisguardGetListResult"`
+	Paging *struct {
+		TotalItems  int `xml:"TotalItems"`
+		CurrentPage int `xml:"CurrentPage"`
+		PageSize    int `xml:"PageSize"`
+	} `xml:"Paging"`
+}
+
+func WhoisguardGetList(client *Client, page int) (*whoisguardGetListCommandResponse, error) {
+	resp, err := Do[whoisguardGetListCommandResponse](client, "namecheap.whoisguard.getList", map[string]string{
+		"ListType": "ALLOTED",
+		"Page":     strconv.Itoa(page),
+		"PageSize": "100",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.CommandResponse, nil
+}
+
+// WhoisguardOf returns the privacy protection allotted to domain, or nil if
+// there is none.
+func WhoisguardOf(client *Client, domain string) (*Whoisguard, error) {
+	for page := 1; ; page++ {
+		resp, err := WhoisguardGetList(client, page)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Result == nil || len(resp.Result.Whoisguards) == 0 {
+			return nil, nil
+		}
+		for _, w := range resp.Result.Whoisguards {
+			if strings.EqualFold(w.DomainName, domain) {
+				return &w, nil
+			}
+		}
+		// Without sane paging there is no telling whether more pages follow.
+		if resp.Paging == nil || resp.Paging.PageSize <= 0 || page*resp.Paging.PageSize >= resp.Paging.TotalItems {
+			return nil, nil
+		}
+	}
+}
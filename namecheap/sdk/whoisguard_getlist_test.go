@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/namecheap/go-namecheap-sdk/v2/namecheap"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+)
+
+func TestWhoisguardOf(t *testing.T) {
+	client := newTestClient(namecheaptest.NewRecorder(t, "testdata"))
+
+	w, err := WhoisguardOf(client, "Example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w == nil || w.ID != "53536" || !w.Enabled() {
+		t.Fatalf("unexpected whoisguard: %+v", w)
+	}
+
+	w, err = WhoisguardOf(client, "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != nil {
+		t.Errorf("expected no whoisguard for example.org, got %+v", w)
+	}
+}
+
+func TestWhoisguardOfPaging(t *testing.T) {
+	for name, tc := range map[string]struct {
+		whoisguards string
+		paging      string
+	}{
+		"no page size":  {`<Whoisguard ID="1" DomainName="example.net" Status="enabled" />`, `<PageSize>0</PageSize><TotalItems>10</TotalItems>`},
+		"no paging":     {`<Whoisguard ID="1" DomainName="example.net" Status="enabled" />`, ``},
+		"empty page":    {``, `<PageSize>100</PageSize><TotalItems>500</TotalItems>`},
+		"missing total": {`<Whoisguard ID="1" DomainName="example.net" Status="enabled" />`, `<PageSize>1</PageSize>`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests > 1 {
+					t.Errorf("unexpected request of page %s", r.FormValue("Page"))
+				}
+				fmt.Fprintf(w, `<ApiResponse Status="OK"><CommandResponse Type="namecheap.whoisguard.getList">`+
+					`<WhoisguardGetListResult>%s</WhoisguardGetListResult><Paging>%s</Paging></CommandResponse></ApiResponse>`,
+					tc.whoisguards, tc.paging)
+			}))
+			defer srv.Close()
+
+			client := NewClient(&namecheap.ClientOptions{UserName: "testuser", ApiUser: "testuser", ApiKey: "testkey", ClientIp: "127.0.0.1"})
+			client.BaseURL = srv.URL
+
+			w, err := WhoisguardOf(client, "example.com")
+			if err != nil || w != nil {
+				t.Errorf("expected no whoisguard, got %+v, %v", w, err)
+			}
+		})
+	}
+}
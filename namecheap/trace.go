@@ -0,0 +1,123 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
+)
+
+// traceSubsystem is the tflog subsystem of the XML API requests. Its level
+// follows TF_LOG_PROVIDER_NAMECHEAP_API, or TF_LOG_PROVIDER when unset.
+const traceSubsystem = "namecheap_api"
+
+// contactFields are the personal details NameCheap takes and returns for the
+// contacts of domains and the addresses of the account. Both the request
+// parameters and the response elements are masked with this list.
+var contactFields = []string{
+	"FirstName", "LastName", "JobTitle", "OrganizationName", "Organization", "Address1", "Address2", "City",
+	"StateProvince", "StateProvinceChoice", "PostalCode", "Zip", "Country", "Phone", "PhoneExt", "Fax",
+	"EmailAddress", "AddressName", "UserName",
+}
+
+// sensitiveParams are the request parameters masked in the trace.
+var sensitiveParams = func() []string {
+	params := []string{"ApiKey", "EPPCode"}
+	for _, contact := range []string{"Registrant", "Tech", "Admin", "AuxBilling", "Billing"} {
+		for _, field := range contactFields {
+			params = append(params, contact+field)
+		}
+	}
+	return params
+}()
+
+// sensitiveElement matches the elements of response bodies with contact
+// details or auth codes, which are masked as a whole.
+var sensitiveElement = regexp.MustCompile(`<(` + strings.Join(append(contactFields, "EPPCode"), "|") + `)>[^<]*</`)
+
+// sensitiveAttribute matches the attributes of response bodies with the
+// account owner or the forwarding address of the domain e-mail, such as in
+// domains.getInfo.
+var sensitiveAttribute = regexp.MustCompile(`(OwnerName|ForwardedTo)="[^"]*"`)
+
+// newTraceContext returns ctx with the trace subsystem and its masking set up.
+func newTraceContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, traceSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER", "NAMECHEAP_API"))
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, traceSubsystem, sensitiveParams...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, traceSubsystem, sensitiveElement, sensitiveAttribute)
+	return ctx
+}
+
+// traceTransport logs every request at TRACE level. The sdk package sends
+// its requests without context, so the logger is taken from ctx, the
+// context the provider was configured with.
+type traceTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fields := map[string]interface{}{}
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			raw, _ := io.ReadAll(body)
+			body.Close()
+			params, _ := url.ParseQuery(string(raw))
+			for name := range params {
+				fields[name] = params.Get(name)
+			}
+		}
+	}
+	fields["command"] = fields["Command"]
+	delete(fields, "Command")
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemTrace(t.ctx, traceSubsystem, "NameCheap API request failed", fields)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	fields["http_status"] = resp.StatusCode
+	fields["response_body"] = string(body)
+	var envelope sdk.ApiResponse[struct{}]
+	if xml.Unmarshal(body, &envelope) == nil {
+		fields["status"] = envelope.Status
+		fields["execution_time"] = envelope.ExecutionTime
+		if len(envelope.Warnings) > 0 {
+			fields["warnings"] = messagesOf(envelope.Warnings)
+		}
+		if len(envelope.Errors) > 0 {
+			fields["errors"] = messagesOf(envelope.Errors)
+		}
+	}
+	tflog.SubsystemTrace(t.ctx, traceSubsystem, "NameCheap API request", fields)
+
+	return resp, nil
+}
+
+func messagesOf(messages []sdk.ApiMessage) []string {
+	result := make([]string, len(messages))
+	for i, m := range messages {
+		result[i] = m.Message + " (" + m.Number + ")"
+	}
+	return result
+}
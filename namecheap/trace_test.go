@@ -0,0 +1,95 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
+)
+
+func TestTraceTransport(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	httpClient, err := newHTTPClient(transportOptions{Trace: newTraceContext(ctx)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := sdk.NewClient(srv.ClientOptions())
+	client.BaseURL = srv.Endpoint()
+	client.HTTPClient = httpClient
+
+	_, err = sdk.Do[struct{}](client, "namecheap.users.address.getInfo", map[string]string{
+		"AddressId":              "0",
+		"RegistrantEmailAddress": "john@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.AddDomain(namecheaptest.Domain{Name: "example.com", Created: time.Now(), Expires: time.Now().AddDate(1, 0, 0)})
+	if _, err := sdk.DomainsGetInfo(client, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	srv.FailNext("namecheap.domains.check", "2011166", "Domain name is invalid")
+	if _, err := sdk.DomainsAvailable(client, "example.com"); err == nil {
+		t.Fatal("expected the command to fail")
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 log entries, got %d: %v", len(entries), entries)
+	}
+
+	getInfo := entries[0]
+	for key, expected := range map[string]interface{}{
+		"@level":                 "trace",
+		"@module":                "provider." + traceSubsystem,
+		"command":                "namecheap.users.address.getInfo",
+		"AddressId":              "0",
+		"ApiUser":                namecheaptest.ApiUser,
+		"ApiKey":                 "***",
+		"RegistrantEmailAddress": "***",
+		"http_status":            float64(200),
+		"status":                 "OK",
+	} {
+		if getInfo[key] != expected {
+			t.Errorf("expected %s to be %v, got %v", key, expected, getInfo[key])
+		}
+	}
+	body, _ := getInfo["response_body"].(string)
+	if !strings.Contains(body, "GetAddressInfoResult") {
+		t.Errorf("expected the response body, got %s", body)
+	}
+	for _, detail := range []string{"<EmailAddress>john@example.com", "<Zip>85284", "<AddressName>Primary Address", "<UserName>" + namecheaptest.UserName} {
+		if strings.Contains(body, detail) {
+			t.Errorf("expected %s to be masked, got %s", detail, body)
+		}
+	}
+
+	domainInfo, _ := entries[1]["response_body"].(string)
+	if !strings.Contains(domainInfo, "DomainGetInfoResult") {
+		t.Errorf("expected the response body, got %s", domainInfo)
+	}
+	if strings.Contains(domainInfo, `OwnerName="`+namecheaptest.UserName+`"`) {
+		t.Errorf("expected OwnerName to be masked, got %s", domainInfo)
+	}
+	if masked := sensitiveAttribute.ReplaceAllString(`<EmailDetails Type="FWD" ForwardedTo="john@example.com" />`, "***"); strings.Contains(masked, "john@example.com") {
+		t.Errorf("expected ForwardedTo to be masked, got %s", masked)
+	}
+
+	check := entries[2]
+	if errors, _ := check["errors"].([]interface{}); len(errors) != 1 || errors[0] != "Domain name is invalid (2011166)" {
+		t.Errorf("expected the reported errors, got %v", check["errors"])
+	}
+}
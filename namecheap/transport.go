@@ -0,0 +1,94 @@
+package namecheap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// transportOptions are the HTTP settings of the requests to NameCheap.
+type transportOptions struct {
+	// Proxy is the URL of the proxy, the proxy environment variables are
+	// honoured when empty.
+	Proxy string
+	// Timeout bounds every request, including reading the response. No
+	// timeout applies when it is 0.
+	Timeout time.Duration
+	// CABundle is the path of PEM encoded certificates trusted in addition
+	// to the system ones.
+	CABundle           string
+	InsecureSkipVerify bool
+	UserAgent          string
+	// Trace is the context to log the requests with, see traceTransport. The
+	// requests are not logged when it is nil.
+	Trace context.Context
+}
+
+// newHTTPClient returns a client with the pooled transport of cleanhttp,
+// customized by opts.
+func newHTTPClient(opts transportOptions) (*http.Client, error) {
+	transport := cleanhttp.DefaultPooledTransport()
+
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("http_proxy %q is not an absolute URL", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if opts.CABundle != "" || opts.InsecureSkipVerify {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// Only meant for mock servers with self-signed certificates.
+			InsecureSkipVerify: opts.InsecureSkipVerify,
+		}
+		if opts.CABundle != "" {
+			pem, err := os.ReadFile(opts.CABundle)
+			if err != nil {
+				return nil, fmt.Errorf("reading ca_bundle: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("ca_bundle %s has no PEM encoded certificates", opts.CABundle)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	var next http.RoundTripper = transport
+	if opts.Trace != nil {
+		next = &traceTransport{ctx: opts.Trace, next: next}
+	}
+
+	return &http.Client{
+		Transport: &userAgentTransport{userAgent: opts.UserAgent, next: next},
+		Timeout:   opts.Timeout,
+	}, nil
+}
+
+// userAgentTransport sets the User-Agent of every request.
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.userAgent == "" {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
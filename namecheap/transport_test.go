@@ -0,0 +1,106 @@
+package namecheap
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/namecheaptest"
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
+)
+
+func TestNewHTTPClientUserAgent(t *testing.T) {
+	srv := namecheaptest.NewServer()
+	defer srv.Close()
+
+	var userAgent string
+	httpClient, err := newHTTPClient(transportOptions{UserAgent: "terraform-provider-st-namecheap/test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := httpClient.Transport.(*userAgentTransport)
+	next := transport.next
+	transport.next = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		userAgent = req.Header.Get("User-Agent")
+		return next.RoundTrip(req)
+	})
+
+	client := sdk.NewClient(srv.ClientOptions())
+	client.BaseURL = srv.Endpoint()
+	client.HTTPClient = httpClient
+	if _, err := sdk.DomainsAvailable(client, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if userAgent != "terraform-provider-st-namecheap/test" {
+		t.Errorf("expected the request to go through the HTTP client, got User-Agent %q", userAgent)
+	}
+}
+
+func TestNewHTTPClientCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		opts     transportOptions
+		expected string
+	}{
+		"untrusted":   {transportOptions{}, "certificate"},
+		"ca bundle":   {transportOptions{CABundle: bundle}, ""},
+		"skip verify": {transportOptions{InsecureSkipVerify: true}, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			httpClient, err := newHTTPClient(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = httpClient.Get(srv.URL)
+			if tc.expected == "" && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tc.expected != "" && (err == nil || !strings.Contains(err.Error(), tc.expected)) {
+				t.Errorf("expected error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	httpClient, err := newHTTPClient(transportOptions{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := httpClient.Get(srv.URL); err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestNewHTTPClientInvalid(t *testing.T) {
+	if _, err := newHTTPClient(transportOptions{Proxy: "proxy.local"}); err == nil {
+		t.Error("expected error for a proxy without scheme")
+	}
+	if _, err := newHTTPClient(transportOptions{CABundle: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("expected error for a missing ca_bundle")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
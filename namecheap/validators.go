@@ -0,0 +1,97 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/net/idna"
+
+	"github.com/myklst/terraform-provider-st-namecheap/namecheap/sdk"
+)
+
+// hostnameProfile validates hostnames the way registries do, including the
+// length limits of labels and names.
+var hostnameProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+)
+
+// hostnameValidator checks that a string is a fully qualified hostname, in
+// either its Unicode or punycode form.
+type hostnameValidator struct{}
+
+func (v hostnameValidator) Description(_ context.Context) string {
+	return "value must be a fully qualified hostname"
+}
+
+func (v hostnameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v hostnameValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	hostname := req.ConfigValue.ValueString()
+	ascii, err := hostnameProfile.ToASCII(strings.TrimSuffix(hostname, "."))
+	if err == nil && !strings.Contains(ascii, ".") {
+		err = fmt.Errorf("hostname has no top-level domain")
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid hostname",
+			fmt.Sprintf("%q is not a valid hostname: %v", hostname, err))
+	}
+}
+
+// positiveFloat64Validator checks that a number is greater than zero.
+type positiveFloat64Validator struct{}
+
+func (v positiveFloat64Validator) Description(_ context.Context) string {
+	return "value must be greater than 0"
+}
+
+func (v positiveFloat64Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v positiveFloat64Validator) ValidateFloat64(_ context.Context, req validator.Float64Request, resp *validator.Float64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if req.ConfigValue.ValueFloat64() <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid value",
+			fmt.Sprintf("%s must be greater than 0, got: %g", req.Path, req.ConfigValue.ValueFloat64()))
+	}
+}
+
+// extendedAttributesValidator checks that extended attributes do not override
+// the core parameters of namecheap.domains.create.
+type extendedAttributesValidator struct{}
+
+func (v extendedAttributesValidator) Description(_ context.Context) string {
+	return "keys must not be core parameters of namecheap.domains.create"
+}
+
+func (v extendedAttributesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v extendedAttributesValidator) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	names := make([]string, 0, len(req.ConfigValue.Elements()))
+	for name := range req.ConfigValue.Elements() {
+		names = append(names, name)
+	}
+	if colliding := sdk.CollidingExtendedAttributes(names); len(colliding) > 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid extended attributes",
+			fmt.Sprintf("[%s] are set by the provider and cannot be extended attributes", strings.Join(colliding, ", ")))
+	}
+}